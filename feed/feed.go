@@ -0,0 +1,90 @@
+// Package feed builds Atom and RSS representations of recent page edits,
+// for subscribers who'd rather watch a feed reader than poll the HTML UI.
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/storage"
+	"golang.org/x/tools/blog/atom"
+)
+
+// Atom builds an Atom feed of revs, newest first, with entry links pointing
+// at siteURL+"/page/"+title. revs is used as given; callers are responsible
+// for ordering and capping it (see storage.PageStore.RecentChanges).
+func Atom(siteURL, title string, revs []*storage.Revision) *atom.Feed {
+	f := &atom.Feed{
+		Title: title,
+		ID:    siteURL + "/feed.atom",
+		Link:  []atom.Link{{Rel: "self", Href: siteURL + "/feed.atom"}},
+	}
+	if len(revs) > 0 {
+		f.Updated = atom.Time(revs[0].Timestamp)
+	}
+	for _, rev := range revs {
+		href := siteURL + "/page/" + rev.Title
+		f.Entry = append(f.Entry, &atom.Entry{
+			Title:     rev.Title,
+			ID:        href + "#" + fmt.Sprint(rev.ID),
+			Link:      []atom.Link{{Href: href}},
+			Published: atom.Time(rev.Timestamp),
+			Updated:   atom.Time(rev.Timestamp),
+			Author:    &atom.Person{Name: rev.Author},
+			Summary:   &atom.Text{Type: "text", Body: summary(rev)},
+		})
+	}
+	return f
+}
+
+// summary returns the diff summary shown for rev: its edit comment, or a
+// placeholder if the author left one blank.
+func summary(rev *storage.Revision) string {
+	if rev.Comment == "" {
+		return fmt.Sprintf("%s edited %s", rev.Author, rev.Title)
+	}
+	return rev.Comment
+}
+
+// RSS is the root element of an RSS 2.0 document, following the same
+// "just enough of the spec to be a valid feed" approach as atom.Feed.
+type RSS struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel is the <channel> element of an RSS feed.
+type RSSChannel struct {
+	Title string    `xml:"title"`
+	Link  string    `xml:"link"`
+	Items []RSSItem `xml:"item"`
+}
+
+// RSSItem is a single <item> entry in an RSS feed.
+type RSSItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Author      string `xml:"author"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// RSS2 builds an RSS 2.0 feed of revs, newest first, mirroring Atom's
+// entry shape and diff-summary convention.
+func RSS2(siteURL, title string, revs []*storage.Revision) *RSS {
+	channel := RSSChannel{Title: title, Link: siteURL}
+	for _, rev := range revs {
+		href := siteURL + "/page/" + rev.Title
+		channel.Items = append(channel.Items, RSSItem{
+			Title:       rev.Title,
+			Link:        href,
+			GUID:        href + "#" + fmt.Sprint(rev.ID),
+			Author:      rev.Author,
+			PubDate:     rev.Timestamp.Format("Mon, 02 Jan 2006 15:04:05 -0700"),
+			Description: summary(rev),
+		})
+	}
+	return &RSS{Version: "2.0", Channel: channel}
+}
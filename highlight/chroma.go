@@ -0,0 +1,69 @@
+// Package highlight implements parser.Highlighter using chroma, a pure-Go
+// syntax highlighting library, so wiki pages don't need a Pygments
+// installation (the highlighter other Go blog engines typically shell out
+// to) for `<syntaxhighlight>`/`<source>` blocks.
+package highlight
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+
+	"github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/parser"
+)
+
+var _ parser.Highlighter = ChromaHighlighter{}
+
+// ChromaHighlighter renders code blocks with chroma, producing
+// `<pre class="chroma">` output with `<span>` token classes keyed to
+// Style. Pair it with StyleCSS(Style) served from a CSS endpoint.
+type ChromaHighlighter struct {
+	// Style is a chroma style name (e.g. "monokai", "github"). Empty uses
+	// chroma's default "swapoff" style via styles.Fallback.
+	Style string
+}
+
+// Highlight implements parser.Highlighter.
+func (h ChromaHighlighter) Highlight(code, lang string) (string, bool) {
+	lexer := lexers.Get(lang)
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	style := styles.Get(h.Style)
+	if style == nil {
+		style = styles.Fallback
+	}
+
+	iterator, err := lexer.Tokenise(nil, code)
+	if err != nil {
+		return "", false
+	}
+
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+	var buf strings.Builder
+	if err := formatter.Format(&buf, style, iterator); err != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// StyleCSS returns the CSS stylesheet for styleName, for serving from an
+// endpoint like `/highlight.css` so ChromaHighlighter's class-based output
+// renders with the right colors.
+func StyleCSS(styleName string) (string, error) {
+	style := styles.Get(styleName)
+	if style == nil {
+		style = styles.Fallback
+	}
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+	var buf strings.Builder
+	if err := formatter.WriteCSS(&buf, style); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
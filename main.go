@@ -1,120 +1,506 @@
 package main
 
 import (
+    "encoding/xml"
     "fmt"
+    "html"
     "html/template"
     "log"
     "net/http"
-    "regexp"
+    "strconv"
     "strings"
 
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/feed"
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/highlight"
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/parser"
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/sanitize"
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/search"
+    "github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/storage"
     "github.com/gorilla/mux"
 )
 
-// ParseMediaWiki converts MediaWiki syntax into HTML
-func ParseMediaWiki(text string) string {
-    // Headings: `= Heading =`, `== Heading ==`, etc.
-    text = parseHeadings(text)
-    
-    // Bold: `'''bold'''`
-    text = parseBold(text)
+// siteTitle and siteURL identify this wiki in the feeds served at
+// /feed.atom and /feed.rss.
+const siteTitle = "Recent changes"
+const siteURL = "http://localhost:8080"
+
+// feedEntryLimit caps how many recent revisions a feed request returns,
+// regardless of how many pages the wiki has accumulated.
+const feedEntryLimit = 50
+
+// wikiPolicy strips any markup a hostile wikilink or syntax-highlight
+// payload could use to inject a script before rendered HTML reaches a
+// browser. A *sanitize.Policy is safe for concurrent use, so one instance
+// is shared across every request.
+var wikiPolicy = sanitize.MediaWikiPolicy()
 
-    // Italic: `''italic''`
-    text = parseItalic(text)
+// store is the PageStore backing every handler below. It's set once in
+// main(), so handlers can assume it's non-nil.
+var store storage.PageStore
 
-    // Internal Links: `[[PageName]]`
-    text = parseInternalLinks(text)
+// searcher is the full-text index kept in sync with store: SavePage indexes
+// every new revision, and ReindexAll re-indexes every one of store's titles.
+// It's set once in main(), so handlers can assume it's non-nil.
+var searcher search.Searcher
 
-    // External Links: `[http://example.com]`
-    text = parseExternalLinks(text)
+// highlightStyle is the chroma style served at /highlight.css and used to
+// render `<syntaxhighlight>` blocks; see renderConfig.
+const highlightStyle = "github"
 
-    return text
+// welcomePage seeds a fresh store so the demo has something to view before
+// anyone has saved a page.
+const welcomePage = `= Welcome to the Wiki =
+This is a simple page about ''Golang''. Visit the '''Golang Page''' by clicking [[Golang]].
+To learn more, visit [https://golang.org].`
+
+// ParseMediaWiki converts MediaWiki syntax into HTML under the package's
+// default configuration (no template expansion, standard namespace
+// aliases, no red-link detection), with the output sanitized by wikiPolicy.
+// Handlers that need red links wired to
+// store.Exists build their own *parser.Config instead; see renderConfig.
+func ParseMediaWiki(text string) string {
+    cfg := parser.DefaultConfig()
+    cfg.Sanitizer = wikiPolicy
+    return parser.RenderMediaWiki(text, cfg)
 }
 
-// Parse headings like `= Heading =` and convert to `<h1>Heading</h1>`, etc.
-func parseHeadings(text string) string {
-    headingRegex := regexp.MustCompile(`(?m)^(={1,6})\s*(.*?)\s*\1$`)
-    return headingRegex.ReplaceAllStringFunc(text, func(match string) string {
-        parts := headingRegex.FindStringSubmatch(match)
-        level := len(parts[1])
-        return fmt.Sprintf("<h%d>%s</h%d>", level, parts[2], level)
-    })
+// renderConfig returns a parser.Config for rendering currentPage, with
+// wikilinks resolved against store so links to missing pages render as red
+// links to their editor.
+func renderConfig(currentPage string) *parser.Config {
+    cfg := parser.DefaultConfig()
+    cfg.CurrentPage = currentPage
+    cfg.Links = parser.ExistenceLinkResolver{Exists: store.Exists}
+    cfg.Highlighter = highlight.ChromaHighlighter{Style: highlightStyle}
+    cfg.Sanitizer = wikiPolicy
+    return cfg
 }
 
-// Parse bold syntax `'''bold'''`
-func parseBold(text string) string {
-    boldRegex := regexp.MustCompile(`'''(.*?)'''`)
-    return boldRegex.ReplaceAllString(text, "<b>$1</b>")
+// pageNamespace returns the canonical namespace a "Prefix:Rest" title
+// belongs to (e.g. "Category" for "Category:Foo"), or "" for a plain title,
+// mirroring the prefixes parser.StandardNamespaces recognizes in wikilinks.
+func pageNamespace(title string) string {
+    prefix, _, ok := strings.Cut(title, ":")
+    if !ok {
+        return ""
+    }
+    return parser.StandardNamespaces[prefix]
 }
 
-// Parse italic syntax `''italic''`
-func parseItalic(text string) string {
-    italicRegex := regexp.MustCompile(`''(.*?)''`)
-    return italicRegex.ReplaceAllString(text, "<i>$1</i>")
+// HighlightCSS serves the stylesheet for the chroma style ChromaHighlighter
+// renders with, so `<syntaxhighlight>` output gets its token colors.
+func HighlightCSS(w http.ResponseWriter, r *http.Request) {
+    css, err := highlight.StyleCSS(highlightStyle)
+    if err != nil {
+        http.Error(w, "Could not render stylesheet", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "text/css; charset=utf-8")
+    fmt.Fprint(w, css)
 }
 
-// Parse internal links `[[PageName]]` to `<a href="/page/PageName">PageName</a>`
-func parseInternalLinks(text string) string {
-    internalLinkRegex := regexp.MustCompile(`\[\[([^\]]+?)\]\]`)
-    return internalLinkRegex.ReplaceAllStringFunc(text, func(match string) string {
-        page := internalLinkRegex.FindStringSubmatch(match)[1]
-        return fmt.Sprintf(`<a href="/page/%s">%s</a>`, page, page)
-    })
+var pageTemplate = template.Must(template.New("page").Parse(`
+    <!DOCTYPE html>
+    <html lang="en">
+    <head>
+        <meta charset="UTF-8">
+        <title>{{ .Title }}</title>
+    </head>
+    <body>
+        <h1>{{ .Title }}</h1>
+        <p><a href="/edit/{{ .Title }}">edit</a> | <a href="/history/{{ .Title }}">history</a></p>
+        <div>{{ .Content }}</div>
+    </body>
+    </html>`))
+
+// ViewPage renders the latest revision of a MediaWiki-formatted page as
+// HTML. A page that has never been saved redirects to its editor, matching
+// the Go wiki tutorial's viewHandler.
+func ViewPage(w http.ResponseWriter, r *http.Request) {
+    title := mux.Vars(r)["title"]
+    if storage.ValidateTitle(title) != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    rev, err := store.Get(title)
+    if err != nil {
+        http.Redirect(w, r, "/edit/"+title, http.StatusFound)
+        return
+    }
+
+    data := struct {
+        Title   string
+        Content template.HTML
+    }{
+        Title:   title,
+        Content: template.HTML(parser.RenderMediaWiki(rev.Body, renderConfig(title))),
+    }
+
+    if err := pageTemplate.Execute(w, data); err != nil {
+        http.Error(w, "Template error", http.StatusInternalServerError)
+    }
 }
 
-// Parse external links `[http://example.com]`
-func parseExternalLinks(text string) string {
-    externalLinkRegex := regexp.MustCompile(`\[(http[^\s]+)\]`)
-    return externalLinkRegex.ReplaceAllString(text, `<a href="$1">$1</a>`)
+var editTemplate = template.Must(template.New("edit").Parse(`
+    <!DOCTYPE html>
+    <html lang="en">
+    <head>
+        <meta charset="UTF-8">
+        <title>Editing {{ .Title }}</title>
+    </head>
+    <body>
+        <h1>Editing {{ .Title }}</h1>
+        <form action="/save/{{ .Title }}" method="POST">
+            <textarea name="body" rows="20" cols="80">{{ .Body }}</textarea><br>
+            <input type="text" name="author" placeholder="Your name"><br>
+            <input type="text" name="comment" placeholder="Edit summary"><br>
+            <input type="submit" value="Save">
+        </form>
+    </body>
+    </html>`))
+
+// EditPage renders a form for creating or revising a page. It's not an
+// error for the page not to exist yet: that's how a new page gets created.
+func EditPage(w http.ResponseWriter, r *http.Request) {
+    title := mux.Vars(r)["title"]
+    if storage.ValidateTitle(title) != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    body := ""
+    if rev, err := store.Get(title); err == nil {
+        body = rev.Body
+    }
+
+    data := struct{ Title, Body string }{Title: title, Body: body}
+    if err := editTemplate.Execute(w, data); err != nil {
+        http.Error(w, "Template error", http.StatusInternalServerError)
+    }
 }
 
-// ViewPage renders a MediaWiki-formatted page in HTML
-func ViewPage(w http.ResponseWriter, r *http.Request) {
-    vars := mux.Vars(r)
-    title := vars["title"]
+// SavePage persists the submitted form as a new revision and redirects to
+// the saved page.
+func SavePage(w http.ResponseWriter, r *http.Request) {
+    title := mux.Vars(r)["title"]
+    if storage.ValidateTitle(title) != nil {
+        http.NotFound(w, r)
+        return
+    }
 
-    // Sample content for demonstration
-    content := `= Welcome to the Wiki =
-This is a simple page about ''Golang''. Visit the '''Golang Page''' by clicking [[Golang]]. 
-To learn more, visit [https://golang.org].`
+    author := r.FormValue("author")
+    if author == "" {
+        author = "anonymous"
+    }
+
+    if _, err := store.Save(title, r.FormValue("body"), author, r.FormValue("comment")); err != nil {
+        http.Error(w, "Could not save page", http.StatusInternalServerError)
+        return
+    }
 
-    // Parse content using MediaWiki parser
-    htmlContent := ParseMediaWiki(content)
+    if err := searcher.Index(title, pageNamespace(title), r.FormValue("body")); err != nil {
+        log.Printf("search: index %q: %v", title, err)
+    }
+
+    http.Redirect(w, r, "/page/"+title, http.StatusFound)
+}
 
-    // Template for displaying the page
-    pageTemplate := `
+var historyTemplate = template.Must(template.New("history").Parse(`
     <!DOCTYPE html>
     <html lang="en">
     <head>
         <meta charset="UTF-8">
-        <title>{{ .Title }}</title>
+        <title>History of {{ .Title }}</title>
+    </head>
+    <body>
+        <h1>History of {{ .Title }}</h1>
+        <ul>
+        {{ range .Revisions }}
+            <li><a href="/revision/{{ $.Title }}/{{ .ID }}">#{{ .ID }}</a> by {{ .Author }} at {{ .Timestamp }} - {{ .Comment }}</li>
+        {{ end }}
+        </ul>
+    </body>
+    </html>`))
+
+// HistoryPage lists every saved revision of a page, oldest first.
+func HistoryPage(w http.ResponseWriter, r *http.Request) {
+    title := mux.Vars(r)["title"]
+    if storage.ValidateTitle(title) != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    revs, err := store.History(title)
+    if err != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    data := struct {
+        Title     string
+        Revisions []*storage.Revision
+    }{Title: title, Revisions: revs}
+
+    if err := historyTemplate.Execute(w, data); err != nil {
+        http.Error(w, "Template error", http.StatusInternalServerError)
+    }
+}
+
+var revisionTemplate = template.Must(template.New("revision").Parse(`
+    <!DOCTYPE html>
+    <html lang="en">
+    <head>
+        <meta charset="UTF-8">
+        <title>Revision #{{ .Revision.ID }} of {{ .Title }}</title>
     </head>
     <body>
         <h1>{{ .Title }}</h1>
+        <p>Revision #{{ .Revision.ID }} by {{ .Revision.Author }} at {{ .Revision.Timestamp }} - {{ .Revision.Comment }}</p>
+        <p><a href="/history/{{ .Title }}">back to history</a> | <a href="/page/{{ .Title }}">current revision</a></p>
         <div>{{ .Content }}</div>
     </body>
-    </html>`
+    </html>`))
 
-    tmpl, err := template.New("page").Parse(pageTemplate)
+// RevisionPage renders a single past revision of a page as it looked at
+// that point in history, so History's revision list links to something.
+func RevisionPage(w http.ResponseWriter, r *http.Request) {
+    title := mux.Vars(r)["title"]
+    if storage.ValidateTitle(title) != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    id, err := strconv.Atoi(mux.Vars(r)["id"])
     if err != nil {
-        http.Error(w, "Template error", http.StatusInternalServerError)
+        http.NotFound(w, r)
+        return
+    }
+
+    rev, err := store.Revision(title, id)
+    if err != nil {
+        http.NotFound(w, r)
         return
     }
 
     data := struct {
-        Title   string
-        Content template.HTML
+        Title    string
+        Revision *storage.Revision
+        Content  template.HTML
     }{
-        Title:   title,
-        Content: template.HTML(htmlContent),
+        Title:    title,
+        Revision: rev,
+        Content:  template.HTML(parser.RenderMediaWiki(rev.Body, renderConfig(title))),
     }
 
-    tmpl.Execute(w, data)
+    if err := revisionTemplate.Execute(w, data); err != nil {
+        http.Error(w, "Template error", http.StatusInternalServerError)
+    }
+}
+
+var searchTemplate = template.Must(template.New("search").Parse(`
+    <!DOCTYPE html>
+    <html lang="en">
+    <head>
+        <meta charset="UTF-8">
+        <title>Search{{ if .Query }}: {{ .Query }}{{ end }}</title>
+    </head>
+    <body>
+        <h1>Search</h1>
+        <form action="/search" method="GET">
+            <input type="text" name="q" value="{{ .Query }}">
+            <input type="submit" value="Search">
+        </form>
+        <ul>
+        {{ range .Results }}
+            <li><a href="/page/{{ .Title }}">{{ .Title }}</a> - {{ .Snippet }}</li>
+        {{ end }}
+        </ul>
+    </body>
+    </html>`))
+
+// searchResultLimit caps how many hits SearchPage shows for a single query.
+const searchResultLimit = 20
+
+// searchResultView mirrors search.Result for the template, except Snippet is
+// pre-escaped template.HTML so the <mark> highlighting MemorySearcher/bleve
+// embed in it renders as markup instead of being escaped to literal text by
+// html/template's autoescaping.
+type searchResultView struct {
+    Title   string
+    Snippet template.HTML
+}
+
+// highlightSnippet escapes everything in snippet except the literal <mark>
+// and </mark> delimiters a Searcher wraps around its match, so the rest of
+// the (untrusted, user-authored) page body can never smuggle in markup.
+func highlightSnippet(snippet string) template.HTML {
+    var b strings.Builder
+    for {
+        start := strings.Index(snippet, "<mark>")
+        if start < 0 {
+            break
+        }
+        end := strings.Index(snippet[start:], "</mark>")
+        if end < 0 {
+            break
+        }
+        end += start
+
+        b.WriteString(html.EscapeString(snippet[:start]))
+        b.WriteString("<mark>")
+        b.WriteString(html.EscapeString(snippet[start+len("<mark>") : end]))
+        b.WriteString("</mark>")
+        snippet = snippet[end+len("</mark>"):]
+    }
+    b.WriteString(html.EscapeString(snippet))
+    return template.HTML(b.String())
+}
+
+// SearchPage answers a full-text query against searcher. The "ns" query
+// parameter, when set, restricts results to that namespace (e.g. "Category").
+func SearchPage(w http.ResponseWriter, r *http.Request) {
+    query := r.URL.Query().Get("q")
+
+    var results []search.Result
+    if query != "" {
+        var err error
+        results, err = searcher.Search(query, r.URL.Query().Get("ns"), searchResultLimit)
+        if err != nil {
+            http.Error(w, "Search failed", http.StatusInternalServerError)
+            return
+        }
+    }
+
+    views := make([]searchResultView, len(results))
+    for i, r := range results {
+        views[i] = searchResultView{Title: r.Title, Snippet: highlightSnippet(r.Snippet)}
+    }
+
+    data := struct {
+        Query   string
+        Results []searchResultView
+    }{Query: query, Results: views}
+
+    if err := searchTemplate.Execute(w, data); err != nil {
+        http.Error(w, "Template error", http.StatusInternalServerError)
+    }
+}
+
+// ReindexAll re-indexes the latest revision of every title in store,
+// replacing each page's entry in searcher, for recovering from a stale index
+// without restarting the server. The index is not cleared first, so entries
+// for titles store doesn't list are left in place. It's a POST since it has
+// side effects and can be slow on a large wiki.
+func ReindexAll(w http.ResponseWriter, r *http.Request) {
+    titles, err := store.Titles()
+    if err != nil {
+        http.Error(w, "Could not list pages", http.StatusInternalServerError)
+        return
+    }
+
+    indexed := 0
+    for _, title := range titles {
+        rev, err := store.Get(title)
+        if err != nil {
+            log.Printf("search: reindex %q: %v", title, err)
+            continue
+        }
+        if err := searcher.Index(title, pageNamespace(title), rev.Body); err != nil {
+            log.Printf("search: reindex %q: %v", title, err)
+            continue
+        }
+        indexed++
+    }
+
+    fmt.Fprintf(w, "Reindexed %d pages\n", indexed)
+}
+
+// recentChanges fetches the feedEntryLimit most recent revisions across
+// every page, optionally filtered to the namespace named by the "ns" query
+// parameter (e.g. "?ns=Category").
+func recentChanges(r *http.Request) ([]*storage.Revision, error) {
+    revs, err := store.RecentChanges(feedEntryLimit)
+    if err != nil {
+        return nil, err
+    }
+
+    ns := r.URL.Query().Get("ns")
+    if ns == "" {
+        return revs, nil
+    }
+    filtered := make([]*storage.Revision, 0, len(revs))
+    for _, rev := range revs {
+        if pageNamespace(rev.Title) == ns {
+            filtered = append(filtered, rev)
+        }
+    }
+    return filtered, nil
+}
+
+// FeedAtom serves recent page edits as an Atom feed.
+func FeedAtom(w http.ResponseWriter, r *http.Request) {
+    revs, err := recentChanges(r)
+    if err != nil {
+        http.Error(w, "Could not load recent changes", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+    fmt.Fprint(w, xml.Header)
+    if err := xml.NewEncoder(w).Encode(feed.Atom(siteURL, siteTitle, revs)); err != nil {
+        log.Printf("feed: encode atom: %v", err)
+    }
+}
+
+// FeedRSS serves recent page edits as an RSS 2.0 feed.
+func FeedRSS(w http.ResponseWriter, r *http.Request) {
+    revs, err := recentChanges(r)
+    if err != nil {
+        http.Error(w, "Could not load recent changes", http.StatusInternalServerError)
+        return
+    }
+
+    w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+    fmt.Fprint(w, xml.Header)
+    if err := xml.NewEncoder(w).Encode(feed.RSS2(siteURL, siteTitle, revs)); err != nil {
+        log.Printf("feed: encode rss: %v", err)
+    }
 }
 
 func main() {
+    fileStore, err := storage.NewFileStore("data")
+    if err != nil {
+        log.Fatal(err)
+    }
+    store = fileStore
+
+    bleveSearcher, err := search.NewBleveSearcher("data/search.bleve")
+    if err != nil {
+        log.Fatal(err)
+    }
+    searcher = bleveSearcher
+
+    if !store.Exists("Welcome") {
+        if _, err := store.Save("Welcome", welcomePage, "system", "Initial import"); err != nil {
+            log.Fatal(err)
+        }
+        if err := searcher.Index("Welcome", "", welcomePage); err != nil {
+            log.Fatal(err)
+        }
+    }
+
     r := mux.NewRouter()
     r.HandleFunc("/page/{title}", ViewPage).Methods("GET")
+    r.HandleFunc("/edit/{title}", EditPage).Methods("GET")
+    r.HandleFunc("/save/{title}", SavePage).Methods("POST")
+    r.HandleFunc("/history/{title}", HistoryPage).Methods("GET")
+    r.HandleFunc("/revision/{title}/{id}", RevisionPage).Methods("GET")
+    r.HandleFunc("/highlight.css", HighlightCSS).Methods("GET")
+    r.HandleFunc("/search", SearchPage).Methods("GET")
+    r.HandleFunc("/admin/reindex", ReindexAll).Methods("POST")
+    r.HandleFunc("/feed.atom", FeedAtom).Methods("GET")
+    r.HandleFunc("/feed.rss", FeedRSS).Methods("GET")
 
     fmt.Println("Server running on http://localhost:8080")
     log.Fatal(http.ListenAndServe(":8080", r))
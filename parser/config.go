@@ -0,0 +1,79 @@
+package parser
+
+// Config controls how Parse and RenderHTML interpret MediaWiki markup that
+// isn't fully determined by the grammar itself: template expansion,
+// namespace aliasing, and link routing.
+type Config struct {
+	// Templates resolves `{{Name|...}}` invocations. Defaults to
+	// NoopTemplateResolver, which leaves unresolved invocations as literal
+	// text.
+	Templates TemplateResolver
+
+	// Namespaces maps a `Prefix:` alias (as typed in `[[Prefix:Target]]`) to
+	// its canonical namespace name. Defaults to a copy of StandardNamespaces.
+	Namespaces map[string]string
+
+	// FileLinkNamespaces lists the canonical namespace names (values of
+	// Namespaces, not aliases) that should render as file/media links
+	// instead of plain page links. Defaults to a copy of FileLinkPrefixes.
+	FileLinkNamespaces []string
+
+	// Links resolves `[[...]]` wikilinks at render time. Defaults to an
+	// ExistenceLinkResolver with no Exists func, i.e. no red-link detection.
+	Links LinkResolver
+
+	// CurrentPage is the title of the page being rendered, passed to Links
+	// as context. Callers re-rendering the same Config for different pages
+	// should set this before each RenderHTML/RenderMediaWiki call.
+	CurrentPage string
+
+	// Highlighter renders `<syntaxhighlight>`/`<source>` blocks. Defaults
+	// to NoopHighlighter, which leaves them as plain `<pre><code>`.
+	Highlighter Highlighter
+
+	// Sanitizer strips dangerous markup from the rendered HTML before
+	// RenderHTML returns it. Defaults to NoopSanitizer, which does nothing;
+	// callers rendering untrusted wikitext for a browser should set this to
+	// a real Sanitizer (see the sanitize package).
+	Sanitizer Sanitizer
+
+	// templateDepth counts levels of nested template expansion during the
+	// current render, so parseTemplate can bail out of runaway (including
+	// mutually) recursive resolvers instead of overflowing the stack. It's
+	// incremented/decremented around each expansion and always back to 0
+	// between renders, so reusing a Config is safe.
+	templateDepth int
+}
+
+// maxTemplateDepth bounds template expansion recursion, matching MediaWiki's
+// own default $wgMaxTemplateDepth.
+const maxTemplateDepth = 40
+
+// DefaultConfig returns a Config seeded with the package's standard
+// namespace aliases and a no-op template resolver.
+func DefaultConfig() *Config {
+	namespaces := make(map[string]string, len(StandardNamespaces))
+	for alias, canonical := range StandardNamespaces {
+		namespaces[alias] = canonical
+	}
+	fileNamespaces := make([]string, len(FileLinkPrefixes))
+	copy(fileNamespaces, FileLinkPrefixes)
+
+	return &Config{
+		Templates:          NoopTemplateResolver{},
+		Namespaces:         namespaces,
+		FileLinkNamespaces: fileNamespaces,
+		Links:              ExistenceLinkResolver{},
+		Highlighter:        NoopHighlighter{},
+		Sanitizer:          NoopSanitizer{},
+	}
+}
+
+func (c *Config) isFileNamespace(namespace string) bool {
+	for _, ns := range c.FileLinkNamespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
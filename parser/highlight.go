@@ -0,0 +1,19 @@
+package parser
+
+// Highlighter renders a code block's contents to HTML, given the language
+// named in `<syntaxhighlight lang="...">`/`<source lang="...">`. ok should
+// be false if the highlighter can't (or chooses not to) handle lang, so the
+// renderer can fall back to an unhighlighted `<pre><code>` block.
+type Highlighter interface {
+	Highlight(code, lang string) (html string, ok bool)
+}
+
+// NoopHighlighter never highlights; every call returns ok == false. It's
+// the default Config.Highlighter so the parser package itself doesn't need
+// a syntax-highlighting dependency.
+type NoopHighlighter struct{}
+
+// Highlight implements Highlighter.
+func (NoopHighlighter) Highlight(code, lang string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var externalLinkRegex = regexp.MustCompile(`^\[(https?://\S+?)(?:\s+([^\]]*))?\]`)
+
+// parseInline scans a run of text for inline constructs - templates, wiki
+// and external links, nowiki spans, signatures, and bold/italic quoting -
+// and returns the resulting sequence of nodes. Plain runs become KindText
+// nodes.
+func parseInline(cfg *Config, text string) []*Node {
+	return parseQuotes(cfg, text)
+}
+
+// parseSpans walks text left to right, peeling off the next recognized
+// construct at each position and leaving everything else as literal text.
+// It does not handle bold/italic quoting; parseQuotes wraps it so that
+// quote runs are resolved first (matching MediaWiki, where an italic span can
+// contain a link but a link's `|` cannot split a quote run).
+func parseSpans(cfg *Config, text string) []*Node {
+	var nodes []*Node
+	var textBuf strings.Builder
+
+	flush := func() {
+		if textBuf.Len() > 0 {
+			nodes = append(nodes, &Node{Kind: KindText, Text: textBuf.String()})
+			textBuf.Reset()
+		}
+	}
+
+	i := 0
+	for i < len(text) {
+		switch {
+		case strings.HasPrefix(text[i:], "~~~~"):
+			flush()
+			nodes = append(nodes, &Node{Kind: KindSignature})
+			i += 4
+
+		case strings.HasPrefix(text[i:], "<nowiki>"):
+			if end := strings.Index(text[i:], "</nowiki>"); end >= 0 {
+				flush()
+				nodes = append(nodes, &Node{Kind: KindNowiki, Text: text[i+len("<nowiki>") : i+end]})
+				i += end + len("</nowiki>")
+			} else {
+				textBuf.WriteByte(text[i])
+				i++
+			}
+
+		case strings.HasPrefix(text[i:], "{{"):
+			if end, ok := matchBalanced(text, i, "{{", "}}"); ok {
+				flush()
+				nodes = append(nodes, parseTemplate(cfg, text[i+2:end-2]))
+				i = end
+			} else {
+				textBuf.WriteByte(text[i])
+				i++
+			}
+
+		case strings.HasPrefix(text[i:], "[["):
+			if end, ok := matchBalanced(text, i, "[[", "]]"); ok {
+				flush()
+				nodes = append(nodes, parseWikiLink(cfg, text[i+2:end-2]))
+				i = end
+			} else {
+				textBuf.WriteByte(text[i])
+				i++
+			}
+
+		case text[i] == '[':
+			if m := externalLinkRegex.FindStringSubmatchIndex(text[i:]); m != nil {
+				flush()
+				href := text[i+m[2] : i+m[3]]
+				display := href
+				if m[4] >= 0 {
+					display = text[i+m[4] : i+m[5]]
+				}
+				nodes = append(nodes, &Node{
+					Kind:    KindExternalLink,
+					Target:  href,
+					Display: []*Node{{Kind: KindText, Text: display}},
+				})
+				i += m[1]
+			} else {
+				textBuf.WriteByte(text[i])
+				i++
+			}
+
+		default:
+			textBuf.WriteByte(text[i])
+			i++
+		}
+	}
+	flush()
+	return nodes
+}
+
+// matchBalanced finds the end (exclusive) of the open/close-delimited span
+// starting at i, honoring nesting of the same delimiters (so
+// `{{Outer|{{Inner}}}}` resolves correctly). ok is false if unterminated.
+func matchBalanced(text string, i int, open, close string) (int, bool) {
+	depth := 0
+	j := i
+	for j < len(text) {
+		switch {
+		case strings.HasPrefix(text[j:], open):
+			depth++
+			j += len(open)
+		case strings.HasPrefix(text[j:], close):
+			depth--
+			j += len(close)
+			if depth == 0 {
+				return j, true
+			}
+		default:
+			j++
+		}
+	}
+	return 0, false
+}
+
+// splitTopLevel splits s on sep, but not inside a nested {{...}} or [[...]]
+// span, so template args and link options can themselves contain templates
+// or links.
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth := 0
+	last := 0
+	i := 0
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], "{{") || strings.HasPrefix(s[i:], "[["):
+			depth++
+			i += 2
+		case strings.HasPrefix(s[i:], "}}") || strings.HasPrefix(s[i:], "]]"):
+			if depth > 0 {
+				depth--
+			}
+			i += 2
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[last:i])
+			i += len(sep)
+			last = i
+		default:
+			i++
+		}
+	}
+	parts = append(parts, s[last:])
+	return parts
+}
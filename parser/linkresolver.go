@@ -0,0 +1,43 @@
+package parser
+
+// LinkResolver decides how a `[[...]]` wikilink renders: its href, an extra
+// CSS class (MediaWiki's convention is "new" for a red link), and whether
+// the target currently exists. It's consulted at render time (not parse
+// time) so the same parsed Document can be re-rendered as pages are
+// created, without reparsing.
+type LinkResolver interface {
+	// ResolveLink is called for every KindLink node. currentPage is the
+	// title of the page being rendered, for resolvers that need context
+	// (e.g. resolving a relative section link). namespace is the node's
+	// canonical namespace (see Node.Namespace), or "" for a plain page
+	// link.
+	ResolveLink(currentPage, namespace, target string) (href, cssClass string, exists bool)
+}
+
+// ExistenceLinkResolver is a LinkResolver that routes Category/User/Talk
+// links to their own path prefix and, for plain page links, consults Exists
+// to decide red-link styling. Exists may be nil, in which case every page
+// link is treated as existing (no red links) - the right default when no
+// PageStore is wired in.
+type ExistenceLinkResolver struct {
+	// Exists reports whether title has a saved page. Typically
+	// storage.PageStore.Exists.
+	Exists func(title string) bool
+}
+
+// ResolveLink implements LinkResolver.
+func (r ExistenceLinkResolver) ResolveLink(currentPage, namespace, target string) (href, cssClass string, exists bool) {
+	switch namespace {
+	case "Category":
+		return "/category/" + target, "", true
+	case "User":
+		return "/user/" + target, "", true
+	case "Talk":
+		return "/talk/" + target, "", true
+	}
+
+	if r.Exists != nil && !r.Exists(target) {
+		return "/edit/" + target, "new", false
+	}
+	return "/page/" + target, "", true
+}
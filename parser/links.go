@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// parseTemplate parses the content between `{{` and `}}` (name and
+// pipe-separated args) and, if cfg.Templates recognizes it, expands and
+// re-parses the result inline.
+func parseTemplate(cfg *Config, inner string) *Node {
+	parts := splitTopLevel(inner, "|")
+	name := strings.TrimSpace(parts[0])
+
+	args := map[string]string{}
+	positional := 0
+	for _, part := range parts[1:] {
+		if key, val, ok := strings.Cut(part, "="); ok {
+			args[strings.TrimSpace(key)] = strings.TrimSpace(val)
+		} else {
+			positional++
+			args[strconv.Itoa(positional)] = strings.TrimSpace(part)
+		}
+	}
+
+	node := &Node{Kind: KindTemplate, TemplateName: name, Attrs: args}
+	if cfg.templateDepth >= maxTemplateDepth {
+		// A resolver may itself return further templates, including ones
+		// that (directly or mutually) reference their own name; bail out
+		// of the recursion rather than expanding forever and overflowing
+		// the stack. The invocation renders as if unresolved.
+		return node
+	}
+	if expansion, ok := cfg.Templates.ResolveTemplate(name, args); ok {
+		node.Resolved = true
+		cfg.templateDepth++
+		node.Children = parseInline(cfg, expansion)
+		cfg.templateDepth--
+	}
+	return node
+}
+
+// parseWikiLink parses the content between `[[` and `]]`: a namespace
+// prefix routed through cfg.Namespaces, the target, an optional `#Section`
+// anchor, an optional `|Display` label, and - for namespaces in
+// cfg.FileLinkNamespaces - file/media options.
+func parseWikiLink(cfg *Config, inner string) *Node {
+	parts := splitTopLevel(inner, "|")
+	rawTarget := strings.TrimSpace(parts[0])
+
+	namespace := ""
+	target := rawTarget
+	if prefix, rest, ok := splitNamespace(rawTarget); ok {
+		if canonical, known := cfg.Namespaces[prefix]; known {
+			namespace = canonical
+			target = strings.TrimSpace(rest)
+		}
+	}
+
+	if namespace != "" && cfg.isFileNamespace(namespace) {
+		return &Node{
+			Kind:        KindFileLink,
+			Target:      target,
+			Namespace:   namespace,
+			FileOptions: trimAll(parts[1:]),
+		}
+	}
+
+	page, section, _ := strings.Cut(target, "#")
+
+	var display []*Node
+	if len(parts) > 1 {
+		display = parseInline(cfg, strings.Join(parts[1:], "|"))
+	} else {
+		display = []*Node{{Kind: KindText, Text: rawTarget}}
+	}
+
+	return &Node{
+		Kind:      KindLink,
+		Target:    strings.TrimSpace(page),
+		Section:   strings.TrimSpace(section),
+		Namespace: namespace,
+		Display:   display,
+	}
+}
+
+// splitNamespace splits "Prefix:Rest" into ("Prefix", "Rest", true), or
+// ("", "", false) if target has no recognized-looking namespace prefix.
+func splitNamespace(target string) (namespace, rest string, ok bool) {
+	prefix, suffix, found := strings.Cut(target, ":")
+	if !found || strings.TrimSpace(prefix) == "" {
+		return "", "", false
+	}
+	return strings.TrimSpace(prefix), suffix, true
+}
+
+func trimAll(ss []string) []string {
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = strings.TrimSpace(s)
+	}
+	return out
+}
@@ -0,0 +1,11 @@
+package parser
+
+// RenderMediaWiki tokenizes, parses, and renders MediaWiki source to HTML in
+// one call - the common case for callers that don't need the intermediate
+// Document (e.g. for a diff view or a search index).
+func RenderMediaWiki(text string, cfg *Config) string {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return RenderHTML(Parse(Tokenize(text), cfg), cfg)
+}
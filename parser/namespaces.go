@@ -0,0 +1,22 @@
+package parser
+
+// StandardNamespaces lists the `Prefix:` link namespaces the renderer
+// recognizes out of the box, mirroring the namespace aliases gowiki ships
+// with. Config.Namespaces starts as a copy of this map so callers can add or
+// override aliases (e.g. a localized wiki adding "Datei" for "File").
+var StandardNamespaces = map[string]string{
+	"File":     "File",
+	"Image":    "File",
+	"Datei":    "File",
+	"Category": "Category",
+	"User":     "User",
+	"Talk":     "Talk",
+	"Template": "Template",
+}
+
+// FileLinkPrefixes names the namespaces whose `[[...]]` targets get
+// file/media rendering (a `<figure>`/`<img>` rather than a plain link).
+// Entries are matched against the canonical namespace an alias resolves to,
+// so "Image" and "Datei" links take this route because they map to "File".
+// Config.FileLinkNamespaces starts as a copy of this slice.
+var FileLinkPrefixes = []string{"File", "Image", "Datei"}
@@ -0,0 +1,62 @@
+package parser
+
+// Kind identifies the type of an AST node produced by Parse.
+type Kind int
+
+const (
+	KindDocument Kind = iota
+	KindHeading
+	KindParagraph
+	KindText
+	KindBold
+	KindItalic
+	KindList
+	KindListItem
+	KindTable
+	KindTableRow
+	KindTableCell
+	KindTemplate
+	KindLink
+	KindFileLink
+	KindExternalLink
+	KindNowiki
+	KindPre
+	KindCodeBlock
+	KindHorizontalRule
+	KindSignature
+)
+
+// Node is a single element of the MediaWiki abstract syntax tree. Not every
+// field applies to every Kind; see the comment on each field for which nodes
+// populate it.
+type Node struct {
+	Kind     Kind
+	Children []*Node
+
+	Text string // KindText, KindNowiki, KindPre, KindCodeBlock: literal content
+
+	Lang string // KindCodeBlock: the `lang="..."` attribute, e.g. "go"
+
+	Level int // KindHeading: 1-6; KindList/KindListItem: nesting depth
+
+	Ordered    bool // KindList: true for `#`, false for `*` (meaningless for a definition list)
+	Definition bool // KindList: true for a `;`/`:` definition list; KindListItem: true for `;` terms and `:` definitions
+	Term       bool // KindListItem: true for `;` terms, false for `:` definitions
+
+	Attrs        map[string]string // KindTable/KindTableRow/KindTableCell: attribute="value" pairs; KindTemplate: named/positional args
+	IsHeaderCell bool              // KindTableCell: true for `!` cells
+
+	TemplateName string // KindTemplate
+	Resolved     bool   // KindTemplate: true if Templates.ResolveTemplate expanded it; Children holds the expansion, otherwise Attrs/TemplateName render literally
+
+	Target      string   // KindLink/KindFileLink: the page/file named between [[ ]]
+	Display     []*Node  // KindLink/KindFileLink/KindExternalLink: rendered label, already inline-parsed
+	Section     string   // KindLink: text after `#` in `[[Page#Section]]`
+	Namespace   string   // KindLink/KindFileLink: the canonical namespace the alias resolved to (e.g. "File", "Category"), or "" for a plain page link
+	FileOptions []string // KindFileLink: pipe-separated options such as "thumb", "caption text"
+}
+
+// Document is the root of a parsed page.
+type Document struct {
+	Root *Node
+}
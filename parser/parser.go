@@ -0,0 +1,256 @@
+package parser
+
+import "strings"
+
+// Parse turns tokenized wikitext into a Document. cfg controls template
+// expansion and link/namespace behavior; pass DefaultConfig() for the
+// out-of-the-box behavior.
+func Parse(tokens []Token, cfg *Config) *Document {
+	p := &parseState{tokens: tokens, cfg: cfg}
+	root := &Node{Kind: KindDocument}
+	for p.pos < len(p.tokens) {
+		if p.peek().Kind == TokenListItem {
+			root.Children = append(root.Children, p.parseList()...)
+			continue
+		}
+		if block := p.parseBlock(); block != nil {
+			root.Children = append(root.Children, block)
+		}
+	}
+	return &Document{Root: root}
+}
+
+type parseState struct {
+	tokens []Token
+	pos    int
+	cfg    *Config
+}
+
+func (p *parseState) peek() *Token {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+// parseBlock consumes and returns the next top-level block, or nil (for a
+// blank line, which is only a separator).
+func (p *parseState) parseBlock() *Node {
+	tok := p.peek()
+	if tok == nil {
+		return nil
+	}
+
+	switch tok.Kind {
+	case TokenBlank:
+		p.pos++
+		return nil
+
+	case TokenHeading:
+		p.pos++
+		return &Node{Kind: KindHeading, Level: tok.HeadingLevel, Children: parseInline(p.cfg, tok.Text)}
+
+	case TokenHorizontalRule:
+		p.pos++
+		return &Node{Kind: KindHorizontalRule}
+
+	case TokenPreStart:
+		return p.parsePre(TokenPreEnd, KindPre)
+	case TokenNowikiStart:
+		return p.parsePre(TokenNowikiEnd, KindNowiki)
+
+	case TokenHighlightStart:
+		return p.parseHighlight()
+
+	case TokenTableStart:
+		return p.parseTable()
+
+	default:
+		return p.parseParagraph()
+	}
+}
+
+// parsePre buffers raw lines until the matching end token (or EOF), for
+// `<pre>`/`<nowiki>` blocks that should not receive inline parsing.
+func (p *parseState) parsePre(end TokenKind, kind Kind) *Node {
+	p.pos++ // consume start token
+	var lines []string
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind != end {
+		lines = append(lines, p.tokens[p.pos].Text)
+		p.pos++
+	}
+	if p.pos < len(p.tokens) {
+		p.pos++ // consume end token
+	}
+	return &Node{Kind: kind, Text: strings.Join(lines, "\n")}
+}
+
+// parseHighlight buffers raw lines until `</syntaxhighlight>`/`</source>`
+// (or EOF) into a KindCodeBlock, recording the opening tag's lang attr.
+func (p *parseState) parseHighlight() *Node {
+	lang := p.tokens[p.pos].Lang
+	p.pos++ // consume start token
+	var lines []string
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind != TokenHighlightEnd {
+		lines = append(lines, p.tokens[p.pos].Text)
+		p.pos++
+	}
+	if p.pos < len(p.tokens) {
+		p.pos++ // consume end token
+	}
+	return &Node{Kind: KindCodeBlock, Lang: lang, Text: strings.Join(lines, "\n")}
+}
+
+// parseParagraph collects consecutive TokenText lines (joined with a single
+// space, as MediaWiki does for soft-wrapped paragraphs) into one paragraph.
+func (p *parseState) parseParagraph() *Node {
+	var lines []string
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind == TokenText {
+		lines = append(lines, p.tokens[p.pos].Text)
+		p.pos++
+	}
+	if len(lines) == 0 {
+		// A block kind parseBlock's default case doesn't otherwise handle;
+		// skip it rather than loop forever.
+		p.pos++
+		return nil
+	}
+	return &Node{Kind: KindParagraph, Children: parseInline(p.cfg, strings.Join(lines, " "))}
+}
+
+// parseList consumes a run of TokenListItem lines, building a forest of
+// KindList/KindListItem trees nested by the length and identity of each
+// item's marker prefix (e.g. "**" nests under "*", "*#" nests an ordered
+// list under an unordered item). A new top-level marker character starts a
+// new tree (e.g. a "*" list followed by a "#" list are siblings, not nested),
+// so this can return more than one root.
+func (p *parseState) parseList() []*Node {
+	type frame struct {
+		list   *Node
+		marker byte
+	}
+	var stack []frame
+	var roots []*Node
+
+	appendList := func(marker byte) *Node {
+		family := listMarkerFamily(marker)
+		list := &Node{Kind: KindList, Ordered: marker == '#', Definition: family == ';', Level: len(stack) + 1}
+		if len(stack) == 0 {
+			roots = append(roots, list)
+		} else {
+			parent := stack[len(stack)-1].list
+			if n := len(parent.Children); n > 0 {
+				parent.Children[n-1].Children = append(parent.Children[n-1].Children, list)
+			} else {
+				parent.Children = append(parent.Children, list)
+			}
+		}
+		stack = append(stack, frame{list: list, marker: family})
+		return list
+	}
+
+	for p.pos < len(p.tokens) && p.tokens[p.pos].Kind == TokenListItem {
+		tok := p.tokens[p.pos]
+		p.pos++
+		markers := tok.ListMarkers
+
+		common := 0
+		for common < len(markers) && common < len(stack) && listMarkerFamily(markers[common]) == stack[common].marker {
+			common++
+		}
+		stack = stack[:common]
+
+		for i := common; i < len(markers); i++ {
+			appendList(markers[i])
+		}
+
+		marker := markers[len(markers)-1]
+		item := &Node{
+			Kind:       KindListItem,
+			Level:      len(markers),
+			Term:       marker == ';',
+			Definition: marker == ';' || marker == ':',
+			Children:   parseInline(p.cfg, tok.Text),
+		}
+		cur := stack[len(stack)-1].list
+		cur.Children = append(cur.Children, item)
+	}
+
+	return roots
+}
+
+// listMarkerFamily groups `;` and `:` together (both belong to the same
+// definition list, a term and its definition) while leaving `*`/`#`
+// distinct, so parseList's common-prefix matching nests consecutive
+// `;`/`:` lines under one KindList instead of starting a new list every
+// time the marker character changes.
+func listMarkerFamily(marker byte) byte {
+	if marker == ';' || marker == ':' {
+		return ';'
+	}
+	return marker
+}
+
+// parseTable consumes a `{| ... |}` block into a KindTable node containing
+// KindTableRow children, each containing KindTableCell children.
+func (p *parseState) parseTable() *Node {
+	startTok := p.tokens[p.pos]
+	p.pos++
+
+	table := &Node{Kind: KindTable, Attrs: startTok.Attrs}
+	var row *Node
+
+	ensureRow := func() *Node {
+		if row == nil {
+			row = &Node{Kind: KindTableRow}
+			table.Children = append(table.Children, row)
+		}
+		return row
+	}
+
+	for p.pos < len(p.tokens) {
+		tok := p.tokens[p.pos]
+		switch tok.Kind {
+		case TokenTableEnd:
+			p.pos++
+			return table
+
+		case TokenTableRowSep:
+			row = &Node{Kind: KindTableRow, Attrs: tok.Attrs}
+			table.Children = append(table.Children, row)
+			p.pos++
+
+		case TokenTableHeaderCell, TokenTableCell:
+			r := ensureRow()
+			for _, cell := range tok.Cells {
+				r.Children = append(r.Children, &Node{
+					Kind:         KindTableCell,
+					IsHeaderCell: tok.Kind == TokenTableHeaderCell,
+					Attrs:        cell.Attrs,
+					Children:     parseInline(p.cfg, cell.Text),
+				})
+			}
+			p.pos++
+
+		case TokenBlank:
+			p.pos++
+
+		default:
+			// Stray content inside a table block we don't otherwise
+			// recognize; treat as part of the current cell's text so it
+			// isn't silently dropped. If there's no preceding cell in this
+			// row to attach it to, give it a bare cell of its own.
+			r := ensureRow()
+			if n := len(r.Children); n > 0 {
+				r.Children[n-1].Children = append(r.Children[n-1].Children, parseInline(p.cfg, tok.Text)...)
+			} else {
+				r.Children = append(r.Children, &Node{
+					Kind:     KindTableCell,
+					Children: parseInline(p.cfg, tok.Text),
+				})
+			}
+			p.pos++
+		}
+	}
+	return table
+}
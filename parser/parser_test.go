@@ -0,0 +1,70 @@
+package parser
+
+import "testing"
+
+func TestRenderMediaWiki(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "definition list groups terms and definitions into one dl",
+			in:   "; Term1\n; Term2\n: Def1\n: Def2",
+			want: "<dl><dt>Term1</dt><dt>Term2</dt><dd>Def1</dd><dd>Def2</dd></dl>",
+		},
+		{
+			name: "nowiki suppresses bold/italic parsing",
+			in:   "Text with <nowiki>''literal''</nowiki> after",
+			want: "<p>Text with &#39;&#39;literal&#39;&#39; after</p>",
+		},
+		{
+			name: "quote runs outside nowiki still parse",
+			in:   "Text with ''italic'' after",
+			want: "<p>Text with <i>italic</i> after</p>",
+		},
+		{
+			name: "Datei namespace resolves to a file link",
+			in:   "[[Datei:foo.png|thumb|a caption]]",
+			want: `<p><figure><img src="/media/foo.png" alt="foo.png"><figcaption>a caption</figcaption></figure></p>`,
+		},
+		{
+			name: "stray text before the first table cell isn't dropped",
+			in:   "{|\nstray text\n|-\n| cell1\n|}",
+			want: "<table><tr><td>stray text</td></tr><tr><td>cell1</td></tr></table>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RenderMediaWiki(tt.in, DefaultConfig()); got != tt.want {
+				t.Errorf("RenderMediaWiki(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSubstituteArgsLongestKeyWins guards against a shorter positional key
+// like $1 prefix-matching and clobbering part of a longer one like $10.
+func TestSubstituteArgsLongestKeyWins(t *testing.T) {
+	args := map[string]string{"1": "one", "10": "ten"}
+	got := substituteArgs("a=$1 b=$10", args)
+	want := "a=one b=ten"
+	if got != want {
+		t.Errorf("substituteArgs = %q, want %q", got, want)
+	}
+}
+
+// TestParseTemplateBoundsRecursion guards against the stack overflow a
+// self-referencing (or mutually recursive) TemplateResolver used to cause:
+// parseTemplate must bail out once maxTemplateDepth is reached instead of
+// expanding forever.
+func TestParseTemplateBoundsRecursion(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Templates = MapTemplateResolver{"Loop": "{{Loop}}"}
+
+	got := RenderMediaWiki("{{Loop}}", cfg)
+	if got == "" {
+		t.Fatal("RenderMediaWiki returned empty output for a recursive template")
+	}
+}
@@ -0,0 +1,140 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+var quoteRunRegex = regexp.MustCompile(`'{2,}`)
+
+// nowikiSpanRegex matches a `<nowiki>...</nowiki>` span the same way
+// parseSpans does (nearest closing tag, not greedy to the last one), so
+// parseQuotes can tell quote-run scanning to treat its contents as opaque.
+var nowikiSpanRegex = regexp.MustCompile(`(?s)<nowiki>.*?</nowiki>`)
+
+// insideAnySpan reports whether pos falls inside one of spans (each a
+// [start, end) pair, as returned by Regexp.FindAllStringIndex), so a
+// run of two or three apostrophes entirely inside a `<nowiki>` block is left as literal text
+// for parseSpans to hand back unparsed, instead of being toggled into a
+// real Bold/Italic node.
+func insideAnySpan(spans [][]int, pos int) bool {
+	for _, s := range spans {
+		if pos >= s[0] && pos < s[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuotes resolves apostrophe runs (two for italic, three for bold, five
+// for bold+italic) before handing the plain-text segments between them to
+// parseSpans. Runs are resolved first (rather than per-segment) so that,
+// matching MediaWiki, an emphasis run can wrap a link or template, as in a
+// bold [[Page]] link.
+//
+// A run of apostrophes toggles italic (2), bold (3), or both at once (5).
+// Toggling a kind that isn't the innermost currently-open one closes and
+// reopens whatever is nested inside it, so e.g. closing bold while an inner
+// italic is still open still nests correctly instead of producing
+// unbalanced tags.
+func parseQuotes(cfg *Config, text string) []*Node {
+	root := &Node{}
+	var stack []*Node // currently open Bold/Italic wrappers, outer to inner
+
+	appendToCurrent := func(n *Node) {
+		if len(stack) == 0 {
+			root.Children = append(root.Children, n)
+			return
+		}
+		top := stack[len(stack)-1]
+		top.Children = append(top.Children, n)
+	}
+
+	toggle := func(kind Kind) {
+		idx := -1
+		for i := len(stack) - 1; i >= 0; i-- {
+			if stack[i].Kind == kind {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			n := &Node{Kind: kind}
+			appendToCurrent(n)
+			stack = append(stack, n)
+			return
+		}
+		reopen := append([]*Node(nil), stack[idx+1:]...)
+		stack = stack[:idx]
+		for _, r := range reopen {
+			n := &Node{Kind: r.Kind}
+			appendToCurrent(n)
+			stack = append(stack, n)
+		}
+	}
+
+	toggleBoth := func() {
+		hasBold, hasItalic := false, false
+		for _, n := range stack {
+			hasBold = hasBold || n.Kind == KindBold
+			hasItalic = hasItalic || n.Kind == KindItalic
+		}
+		if hasBold && hasItalic {
+			// Bold and Italic are the only kinds ever pushed, so both open
+			// means the stack holds exactly these two: close both directly
+			// rather than toggling one at a time, which would otherwise
+			// "reopen" the other only to immediately close it again.
+			stack = stack[:0]
+			return
+		}
+		toggle(KindItalic)
+		toggle(KindBold)
+	}
+
+	flushText := func(buf *strings.Builder) {
+		if buf.Len() == 0 {
+			return
+		}
+		for _, n := range parseSpans(cfg, buf.String()) {
+			appendToCurrent(n)
+		}
+		buf.Reset()
+	}
+
+	nowikiSpans := nowikiSpanRegex.FindAllStringIndex(text, -1)
+
+	var buf strings.Builder
+	last := 0
+	for _, loc := range quoteRunRegex.FindAllStringIndex(text, -1) {
+		if insideAnySpan(nowikiSpans, loc[0]) {
+			continue
+		}
+		buf.WriteString(text[last:loc[0]])
+		last = loc[1]
+		run := loc[1] - loc[0]
+
+		switch {
+		case run == 4:
+			buf.WriteByte('\'')
+			flushText(&buf)
+			toggle(KindBold)
+		case run >= 6:
+			buf.WriteString(strings.Repeat("'", run-5))
+			flushText(&buf)
+			toggleBoth()
+		case run == 5:
+			flushText(&buf)
+			toggleBoth()
+		case run == 3:
+			flushText(&buf)
+			toggle(KindBold)
+		default: // run == 2
+			flushText(&buf)
+			toggle(KindItalic)
+		}
+	}
+	buf.WriteString(text[last:])
+	flushText(&buf)
+
+	return root.Children
+}
@@ -0,0 +1,194 @@
+package parser
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// RenderHTML walks a parsed Document and renders it to an HTML string. cfg
+// is the same Config passed to Parse (it's needed again here for namespace
+// routing of KindLink nodes that weren't resolved to file links at parse
+// time).
+func RenderHTML(doc *Document, cfg *Config) string {
+	var b strings.Builder
+	renderChildren(&b, cfg, doc.Root.Children)
+	if cfg.Sanitizer != nil {
+		return cfg.Sanitizer.Sanitize(b.String())
+	}
+	return b.String()
+}
+
+func renderChildren(b *strings.Builder, cfg *Config, nodes []*Node) {
+	for _, n := range nodes {
+		renderNode(b, cfg, n)
+	}
+}
+
+func renderNode(b *strings.Builder, cfg *Config, n *Node) {
+	switch n.Kind {
+	case KindText:
+		b.WriteString(html.EscapeString(n.Text))
+
+	case KindHeading:
+		fmt.Fprintf(b, "<h%d>", n.Level)
+		renderChildren(b, cfg, n.Children)
+		fmt.Fprintf(b, "</h%d>", n.Level)
+
+	case KindParagraph:
+		b.WriteString("<p>")
+		renderChildren(b, cfg, n.Children)
+		b.WriteString("</p>")
+
+	case KindBold:
+		b.WriteString("<b>")
+		renderChildren(b, cfg, n.Children)
+		b.WriteString("</b>")
+
+	case KindItalic:
+		b.WriteString("<i>")
+		renderChildren(b, cfg, n.Children)
+		b.WriteString("</i>")
+
+	case KindHorizontalRule:
+		b.WriteString("<hr>")
+
+	case KindSignature:
+		b.WriteString(`<span class="signature">~~~~</span>`)
+
+	case KindNowiki:
+		b.WriteString(html.EscapeString(n.Text))
+
+	case KindPre:
+		b.WriteString("<pre>")
+		b.WriteString(html.EscapeString(n.Text))
+		b.WriteString("</pre>")
+
+	case KindCodeBlock:
+		if highlighted, ok := cfg.Highlighter.Highlight(n.Text, n.Lang); ok {
+			b.WriteString(highlighted)
+		} else {
+			b.WriteString("<pre><code>")
+			b.WriteString(html.EscapeString(n.Text))
+			b.WriteString("</code></pre>")
+		}
+
+	case KindList:
+		tag := "ul"
+		if n.Definition {
+			tag = "dl"
+		} else if n.Ordered {
+			tag = "ol"
+		}
+		fmt.Fprintf(b, "<%s>", tag)
+		renderChildren(b, cfg, n.Children)
+		fmt.Fprintf(b, "</%s>", tag)
+
+	case KindListItem:
+		tag := "li"
+		if n.Term {
+			tag = "dt"
+		} else if n.Definition {
+			tag = "dd"
+		}
+		fmt.Fprintf(b, "<%s>", tag)
+		renderChildren(b, cfg, n.Children)
+		fmt.Fprintf(b, "</%s>", tag)
+
+	case KindTable:
+		b.WriteString("<table")
+		writeAttrs(b, n.Attrs)
+		b.WriteString(">")
+		renderChildren(b, cfg, n.Children)
+		b.WriteString("</table>")
+
+	case KindTableRow:
+		b.WriteString("<tr")
+		writeAttrs(b, n.Attrs)
+		b.WriteString(">")
+		renderChildren(b, cfg, n.Children)
+		b.WriteString("</tr>")
+
+	case KindTableCell:
+		tag := "td"
+		if n.IsHeaderCell {
+			tag = "th"
+		}
+		fmt.Fprintf(b, "<%s", tag)
+		writeAttrs(b, n.Attrs)
+		b.WriteString(">")
+		renderChildren(b, cfg, n.Children)
+		fmt.Fprintf(b, "</%s>", tag)
+
+	case KindTemplate:
+		if n.Resolved {
+			renderChildren(b, cfg, n.Children)
+			return
+		}
+		b.WriteString(html.EscapeString(renderRawTemplate(n)))
+
+	case KindLink:
+		renderLink(b, cfg, n)
+
+	case KindFileLink:
+		renderFileLink(b, cfg, n)
+
+	case KindExternalLink:
+		fmt.Fprintf(b, `<a href="%s" rel="nofollow">`, html.EscapeString(n.Target))
+		renderChildren(b, cfg, n.Display)
+		b.WriteString("</a>")
+	}
+}
+
+func renderLink(b *strings.Builder, cfg *Config, n *Node) {
+	href, cssClass, _ := cfg.Links.ResolveLink(cfg.CurrentPage, n.Namespace, n.Target)
+	if n.Section != "" {
+		href += "#" + n.Section
+	}
+	class := ""
+	if cssClass != "" {
+		class = fmt.Sprintf(` class="%s"`, html.EscapeString(cssClass))
+	}
+	fmt.Fprintf(b, `<a href="%s"%s>`, html.EscapeString(href), class)
+	renderChildren(b, cfg, n.Display)
+	b.WriteString("</a>")
+}
+
+func renderFileLink(b *strings.Builder, cfg *Config, n *Node) {
+	b.WriteString("<figure>")
+	fmt.Fprintf(b, `<img src="/media/%s" alt="%s">`, html.EscapeString(n.Target), html.EscapeString(n.Target))
+	for _, opt := range n.FileOptions {
+		if opt != "thumb" && opt != "frame" && !strings.HasPrefix(opt, "px") {
+			fmt.Fprintf(b, "<figcaption>%s</figcaption>", html.EscapeString(opt))
+		}
+	}
+	b.WriteString("</figure>")
+}
+
+func renderRawTemplate(n *Node) string {
+	var b strings.Builder
+	b.WriteString("{{")
+	b.WriteString(n.TemplateName)
+	keys := make([]string, 0, len(n.Attrs))
+	for k := range n.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "|%s=%s", k, n.Attrs[k])
+	}
+	b.WriteString("}}")
+	return b.String()
+}
+
+func writeAttrs(b *strings.Builder, attrs map[string]string) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, ` %s="%s"`, k, html.EscapeString(attrs[k]))
+	}
+}
@@ -0,0 +1,20 @@
+package parser
+
+// Sanitizer strips dangerous markup from rendered HTML before it reaches a
+// browser - scripts, event handler attributes, javascript: URLs, and
+// anything else not on an allow list. RenderMediaWiki and RenderHTML run
+// their output through Config.Sanitizer as a final pass.
+type Sanitizer interface {
+	Sanitize(html string) string
+}
+
+// NoopSanitizer passes HTML through unchanged. It's the default
+// Config.Sanitizer so the parser package itself doesn't need an HTML
+// sanitization dependency; callers serving untrusted wikitext to browsers
+// should set a real one (see the sanitize package).
+type NoopSanitizer struct{}
+
+// Sanitize implements Sanitizer.
+func (NoopSanitizer) Sanitize(html string) string {
+	return html
+}
@@ -0,0 +1,57 @@
+package parser
+
+import (
+	"regexp"
+)
+
+// TemplateResolver expands a `{{Name|arg=val|...}}` invocation into wikitext.
+// The returned text is re-parsed as MediaWiki markup, so a resolver may
+// itself return further templates or inline markup. Implementations that
+// don't recognize name should return ok == false so the renderer can fall
+// back to printing the invocation as literal text.
+type TemplateResolver interface {
+	ResolveTemplate(name string, args map[string]string) (text string, ok bool)
+}
+
+// NoopTemplateResolver never resolves a template; the renderer falls back to
+// printing the invocation as literal text rebuilt from its name and args
+// (e.g. "{{Name|arg=val}}"): args are sorted by key, whitespace around them
+// is trimmed, and positional args print as "1=...", "2=...". It is the
+// default when no resolver is configured.
+type NoopTemplateResolver struct{}
+
+// ResolveTemplate implements TemplateResolver.
+func (NoopTemplateResolver) ResolveTemplate(name string, args map[string]string) (string, bool) {
+	return "", false
+}
+
+// MapTemplateResolver resolves templates from a static name->text lookup.
+// `$1`, `$2`, ... in the stored text are substituted with positional args,
+// and `$name` with named args, before the result is re-parsed.
+type MapTemplateResolver map[string]string
+
+// ResolveTemplate implements TemplateResolver.
+func (m MapTemplateResolver) ResolveTemplate(name string, args map[string]string) (string, bool) {
+	text, ok := m[name]
+	if !ok {
+		return "", false
+	}
+	return substituteArgs(text, args), true
+}
+
+// templateArgRegex matches a `$name` placeholder in stored template text.
+var templateArgRegex = regexp.MustCompile(`\$(\w+)`)
+
+// substituteArgs replaces every `$name` placeholder in text with its value
+// from args in a single pass, so a positional key like `$1` can't
+// prefix-match and clobber part of a longer one like `$10` depending on map
+// iteration order. Placeholders with no matching arg are left as-is.
+func substituteArgs(text string, args map[string]string) string {
+	return templateArgRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+		key := placeholder[1:]
+		if val, ok := args[key]; ok {
+			return val
+		}
+		return placeholder
+	})
+}
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// TokenKind classifies one logical line of wikitext.
+type TokenKind int
+
+const (
+	TokenText TokenKind = iota
+	TokenBlank
+	TokenHeading
+	TokenListItem
+	TokenHorizontalRule
+	TokenTableStart
+	TokenTableEnd
+	TokenTableRowSep
+	TokenTableCell
+	TokenTableHeaderCell
+	TokenPreStart
+	TokenPreEnd
+	TokenNowikiStart
+	TokenNowikiEnd
+	TokenHighlightStart
+	TokenHighlightEnd
+)
+
+// Token is one tokenized line of input. Line-level constructs (headings,
+// list items, table syntax, horizontal rules) are recognized here; inline
+// constructs (bold, italic, links, templates) are parsed later from each
+// token's Text by parseInline.
+type Token struct {
+	Kind TokenKind
+
+	Text string // TokenText/TokenHeading/TokenListItem: content after the marker
+
+	HeadingLevel int // TokenHeading: 1-6
+
+	ListMarkers string // TokenListItem: the raw marker run, e.g. "*#;"
+
+	Attrs map[string]string // TokenTableStart/TokenTableRowSep: attribute="value" pairs (cell attributes are in Cells)
+
+	Cells []tableCellSource // TokenTableHeaderCell/TokenTableCell: one or more `||`/`!!`-separated cells on the line
+
+	Lang string // TokenHighlightStart: the `lang="..."` attribute, e.g. "go"
+}
+
+type tableCellSource struct {
+	Attrs map[string]string
+	Text  string
+}
+
+var (
+	headingRegex     = regexp.MustCompile(`^(={1,6})\s*(.*?)\s*={1,6}$`)
+	hrRegex          = regexp.MustCompile(`^-{4,}$`)
+	listItemRegex    = regexp.MustCompile(`^([*#;:]+)\s*(.*)$`)
+	tableOpenRe      = regexp.MustCompile(`^\{\|\s*(.*)$`)
+	tableRowSepRe    = regexp.MustCompile(`^\|-+\s*(.*)$`)
+	tableHeaderRe    = regexp.MustCompile(`^!\s*(.*)$`)
+	tableCellRe      = regexp.MustCompile(`^\|\s*(.*)$`)
+	highlightOpenRe  = regexp.MustCompile(`(?i)^<(?:syntaxhighlight|source)(\s[^>]*)?>$`)
+	highlightCloseRe = regexp.MustCompile(`(?i)^</(?:syntaxhighlight|source)>$`)
+	langAttrRe       = regexp.MustCompile(`lang\s*=\s*"([^"]*)"|lang\s*=\s*(\S+)`)
+)
+
+// Tokenize splits raw MediaWiki source into line-based tokens. Multi-line
+// constructs such as `{{{`-free `<pre>`/`<nowiki>` blocks are flagged with
+// start/end tokens so Parse can buffer their literal contents.
+func Tokenize(text string) []Token {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	tokens := make([]Token, 0, len(lines))
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			tokens = append(tokens, Token{Kind: TokenBlank})
+
+		case trimmed == "<pre>":
+			tokens = append(tokens, Token{Kind: TokenPreStart})
+		case trimmed == "</pre>":
+			tokens = append(tokens, Token{Kind: TokenPreEnd})
+		case trimmed == "<nowiki>":
+			tokens = append(tokens, Token{Kind: TokenNowikiStart})
+		case trimmed == "</nowiki>":
+			tokens = append(tokens, Token{Kind: TokenNowikiEnd})
+
+		case highlightOpenRe.MatchString(trimmed):
+			m := highlightOpenRe.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenHighlightStart, Lang: langAttr(m[1])})
+		case highlightCloseRe.MatchString(trimmed):
+			tokens = append(tokens, Token{Kind: TokenHighlightEnd})
+
+		case hrRegex.MatchString(trimmed):
+			tokens = append(tokens, Token{Kind: TokenHorizontalRule})
+
+		case headingRegex.MatchString(trimmed):
+			m := headingRegex.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenHeading, HeadingLevel: len(m[1]), Text: m[2]})
+
+		case tableOpenRe.MatchString(trimmed):
+			m := tableOpenRe.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenTableStart, Attrs: parseAttrs(m[1])})
+
+		case trimmed == "|}":
+			tokens = append(tokens, Token{Kind: TokenTableEnd})
+
+		case tableRowSepRe.MatchString(trimmed):
+			m := tableRowSepRe.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenTableRowSep, Attrs: parseAttrs(m[1])})
+
+		case tableHeaderRe.MatchString(trimmed):
+			m := tableHeaderRe.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenTableHeaderCell, Cells: splitTableCells(m[1], "!!")})
+
+		case tableCellRe.MatchString(trimmed):
+			m := tableCellRe.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenTableCell, Cells: splitTableCells(m[1], "||")})
+
+		case listItemRegex.MatchString(trimmed):
+			m := listItemRegex.FindStringSubmatch(trimmed)
+			tokens = append(tokens, Token{Kind: TokenListItem, ListMarkers: m[1], Text: m[2]})
+
+		default:
+			tokens = append(tokens, Token{Kind: TokenText, Text: line})
+		}
+	}
+
+	return tokens
+}
+
+// splitTableCells splits a `|`/`!` row's remainder on the given separator,
+// further splitting each cell on the first "|" into attrs and content (a
+// cell with no "|" has no attributes).
+func splitTableCells(rest, sep string) []tableCellSource {
+	parts := strings.Split(rest, sep)
+	cells := make([]tableCellSource, 0, len(parts))
+	for _, part := range parts {
+		if attrs, content, ok := splitCellAttrs(part); ok {
+			cells = append(cells, tableCellSource{Attrs: parseAttrs(attrs), Text: strings.TrimSpace(content)})
+		} else {
+			cells = append(cells, tableCellSource{Text: strings.TrimSpace(part)})
+		}
+	}
+	return cells
+}
+
+// splitCellAttrs recognizes MediaWiki's `attr=val|content` cell syntax. A
+// bare "|" inside a link like `[[Page|Display]]` would false-positive here,
+// so we only treat the first "|" as an attribute separator when the text
+// before it looks like `key="val"` pairs rather than wikitext.
+func splitCellAttrs(part string) (attrs, content string, ok bool) {
+	idx := strings.Index(part, "|")
+	if idx < 0 {
+		return "", "", false
+	}
+	head := part[:idx]
+	if !strings.Contains(head, "=") || strings.Contains(head, "[[") {
+		return "", "", false
+	}
+	return head, part[idx+1:], true
+}
+
+var attrRegex = regexp.MustCompile(`(\w+)\s*=\s*"([^"]*)"|(\w+)\s*=\s*(\S+)`)
+
+// parseAttrs parses `key="value" key2=value2` style attribute lists used by
+// table/row/cell markup.
+func parseAttrs(s string) map[string]string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	attrs := map[string]string{}
+	for _, m := range attrRegex.FindAllStringSubmatch(s, -1) {
+		if m[1] != "" {
+			attrs[m[1]] = m[2]
+		} else {
+			attrs[m[3]] = m[4]
+		}
+	}
+	if len(attrs) == 0 {
+		return nil
+	}
+	return attrs
+}
+
+// langAttr extracts the `lang="..."` value from a `<syntaxhighlight ...>`
+// opening tag's attribute text, or "" if none was given.
+func langAttr(attrText string) string {
+	m := langAttrRe.FindStringSubmatch(attrText)
+	if m == nil {
+		return ""
+	}
+	if m[1] != "" {
+		return m[1]
+	}
+	return m[2]
+}
@@ -0,0 +1,74 @@
+// Package sanitize implements parser.Sanitizer using bluemonday, stripping
+// anything a hostile wikilink or syntax-highlight payload could use to
+// inject a script, so the parser's output is safe to drop straight into a
+// browser via template.HTML.
+package sanitize
+
+import (
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+
+	"github.com/AzureCloudMonk/golang-mediawiki-to-html-parser/parser"
+)
+
+var _ parser.Sanitizer = (*Policy)(nil)
+
+// hrefPattern restricts <a href> to absolute http(s)/mailto URLs or one of
+// the internal /page/, /edit/, /category/, /user/ and /talk/ paths, covering
+// the links renderLink (via ExistenceLinkResolver) and renderNode's external
+// link case produce.
+var hrefPattern = regexp.MustCompile(`^(?:https?://|mailto:|/page/|/edit/|/category/|/user/|/talk/)`)
+
+// srcPattern restricts <img src> to absolute http(s) URLs or a path starting
+// with "/", ruling out javascript:/data: payloads.
+var srcPattern = regexp.MustCompile(`^(?:https?://|/)`)
+
+// Policy sanitizes rendered MediaWiki HTML with an allow list tailored to
+// what RenderHTML actually emits. The zero value is not ready to use;
+// construct one with MediaWikiPolicy. The embedded *bluemonday.Policy is
+// exported so callers can AllowElements/AllowAttrs further before first use.
+type Policy struct {
+	*bluemonday.Policy
+}
+
+// tableStyleProperties lists the CSS properties allowed in a table/cell
+// `style="..."` attribute (e.g. `{| class="wikitable"` / `| style="..." |`
+// cells) - enough for typical wikitable styling without passing arbitrary
+// CSS through.
+var tableStyleProperties = []string{"text-align", "vertical-align", "width", "background-color", "color", "border"}
+
+// MediaWikiPolicy returns a Policy allowing headings, emphasis, links
+// (href restricted to http/https/mailto and the wiki's own /page/, /edit/,
+// /category/, /user/, /talk/ paths), tables (with the class/style attributes
+// `{| ... |}`/cell syntax supports), figures and images (src restricted to
+// http/https URLs and paths starting with "/"), and pre/code blocks -
+// including the class attributes on renderNode's signature `<span>` and on
+// ChromaHighlighter's `<pre class="chroma">`/`<span class="...">` output.
+func MediaWikiPolicy() *Policy {
+	p := bluemonday.NewPolicy()
+
+	p.AllowElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowElements("b", "i", "p", "br", "hr")
+	p.AllowElements("ul", "ol", "li", "dl", "dt", "dd")
+	p.AllowElements("table", "thead", "tbody", "tr", "td", "th")
+	p.AllowElements("figure", "figcaption")
+
+	p.AllowAttrs("href").Matching(hrefPattern).OnElements("a")
+	p.AllowElements("a")
+
+	p.AllowAttrs("src").Matching(srcPattern).OnElements("img")
+	p.AllowAttrs("alt", "width", "height").OnElements("img")
+	p.AllowElements("img")
+
+	p.AllowElements("pre", "code", "span")
+	p.AllowAttrs("class").OnElements("pre", "code", "span", "figure", "table", "tr", "td", "th")
+	p.AllowStyles(tableStyleProperties...).OnElements("table", "tr", "td", "th")
+
+	return &Policy{Policy: p}
+}
+
+// Sanitize implements parser.Sanitizer.
+func (p *Policy) Sanitize(html string) string {
+	return p.Policy.Sanitize(html)
+}
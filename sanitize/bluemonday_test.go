@@ -0,0 +1,112 @@
+package sanitize
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestMediaWikiPolicy(t *testing.T) {
+	policy := MediaWikiPolicy()
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "script tags are stripped",
+			in:   `<p>hi</p><script>alert(1)</script>`,
+			want: `<p>hi</p>`,
+		},
+		{
+			name: "javascript: href is stripped",
+			in:   `<a href="javascript:alert(1)">x</a>`,
+			want: `x`,
+		},
+		{
+			name: "event handler attribute is stripped",
+			in:   `<img src="/media/foo.png" onerror="alert(1)">`,
+			want: `<img src="/media/foo.png">`,
+		},
+		{
+			name: "http link and internal /page/ link survive",
+			in:   `<a href="https://golang.org">go</a> <a href="/page/Golang">wiki</a>`,
+			want: `<a href="https://golang.org">go</a> <a href="/page/Golang">wiki</a>`,
+		},
+		{
+			name: "table class and cell style survive",
+			in:   `<table class="wikitable"><tr><td style="text-align:right">42</td></tr></table>`,
+			want: `<table class="wikitable"><tr><td style="text-align: right">42</td></tr></table>`,
+		},
+		{
+			name: "chroma's class-based highlight output survives",
+			in:   `<pre class="chroma"><code><span class="nx">fmt</span></code></pre>`,
+			want: `<pre class="chroma"><code><span class="nx">fmt</span></code></pre>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Sanitize(tt.in); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// tagRegex matches a single HTML start tag (e.g. `<a href="...">`), so
+// FuzzSanitize can scope its checks to actual attribute context instead of
+// scanning the whole rendered string - inert plain text like "jAvAsCript:0"
+// or "OnA=0" must not fail the check just because it isn't inside a tag.
+var tagRegex = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+
+// eventHandlerAttrRegex matches an on*="..." attribute within a tag, e.g.
+// onclick=, onerror=, onload=.
+var eventHandlerAttrRegex = regexp.MustCompile(`\bon[a-z]+\s*=`)
+
+// hrefOrSrcAttrRegex captures the value of an href="..."/src="..." attribute
+// within a tag, so FuzzSanitize can check the actual URL context a browser
+// would act on.
+var hrefOrSrcAttrRegex = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*"([^"]*)"`)
+
+// FuzzSanitize asserts that no <script> tag, javascript: URL in an
+// href/src attribute, or inline event handler attribute survives
+// MediaWikiPolicy, regardless of input.
+func FuzzSanitize(f *testing.F) {
+	seeds := []string{
+		`<script>alert(1)</script>`,
+		`<a href="javascript:alert(1)">x</a>`,
+		`<img src=x onerror=alert(1)>`,
+		`<p onclick="alert(1)">hi</p>`,
+		`<svg onload=alert(1)>`,
+		`<a href="http://example.com">ok</a>`,
+		`<table class="wikitable"><tr><td style="text-align:right">42</td></tr></table>`,
+		`plain text with no markup at all`,
+		`jAvAsCript:0`,
+		`OnA=0`,
+	}
+	for _, s := range seeds {
+		f.Add(s)
+	}
+
+	policy := MediaWikiPolicy()
+	f.Fuzz(func(t *testing.T, input string) {
+		out := policy.Sanitize(input)
+		lower := strings.ToLower(out)
+
+		if strings.Contains(lower, "<script") {
+			t.Fatalf("script tag survived sanitization: %q -> %q", input, out)
+		}
+		for _, tag := range tagRegex.FindAllString(lower, -1) {
+			for _, m := range hrefOrSrcAttrRegex.FindAllStringSubmatch(tag, -1) {
+				if strings.HasPrefix(strings.TrimSpace(m[1]), "javascript:") {
+					t.Fatalf("javascript: URL survived sanitization: %q -> %q", input, out)
+				}
+			}
+			if eventHandlerAttrRegex.MatchString(tag) {
+				t.Fatalf("event handler attribute survived sanitization: %q -> %q", input, out)
+			}
+		}
+	})
+}
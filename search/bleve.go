@@ -0,0 +1,109 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+var _ Searcher = (*BleveSearcher)(nil)
+
+// BleveSearcher is a Searcher backed by a bleve full-text index, for
+// production use where the in-memory MemorySearcher's O(n) substring scan
+// wouldn't scale.
+type BleveSearcher struct {
+	index bleve.Index
+}
+
+// bleveDoc is the document shape indexed for each page.
+type bleveDoc struct {
+	Title     string `json:"title"`
+	Namespace string `json:"namespace"`
+	Body      string `json:"body"`
+}
+
+// NewBleveSearcher opens the bleve index at path, creating it with a
+// default mapping if it doesn't exist yet. Pass "" for an in-memory index
+// (useful for tests that still want real bleve query semantics).
+func NewBleveSearcher(path string) (*BleveSearcher, error) {
+	if path == "" {
+		idx, err := bleve.NewMemOnly(bleve.NewIndexMapping())
+		if err != nil {
+			return nil, fmt.Errorf("search: create in-memory index: %w", err)
+		}
+		return &BleveSearcher{index: idx}, nil
+	}
+
+	idx, err := bleve.Open(path)
+	if err == nil {
+		return &BleveSearcher{index: idx}, nil
+	}
+
+	idx, err = bleve.New(path, bleve.NewIndexMapping())
+	if err != nil {
+		return nil, fmt.Errorf("search: create index at %q: %w", path, err)
+	}
+	return &BleveSearcher{index: idx}, nil
+}
+
+// Close releases the underlying index.
+func (s *BleveSearcher) Close() error {
+	return s.index.Close()
+}
+
+// Index implements Searcher.
+func (s *BleveSearcher) Index(title, namespace, body string) error {
+	return s.index.Index(title, bleveDoc{Title: title, Namespace: namespace, Body: body})
+}
+
+// Delete implements Searcher.
+func (s *BleveSearcher) Delete(title string) error {
+	return s.index.Delete(title)
+}
+
+// Search implements Searcher.
+func (s *BleveSearcher) Search(q, namespace string, limit int) ([]Result, error) {
+	textQuery := bleve.NewQueryStringQuery(q)
+
+	var searchQuery query.Query = textQuery
+	if namespace != "" {
+		// A match query runs namespace through the field's analyzer, so it
+		// meets the indexed (lowercased) term; a term query would not.
+		nsQuery := bleve.NewMatchQuery(namespace)
+		nsQuery.SetField("namespace")
+		searchQuery = bleve.NewConjunctionQuery(textQuery, nsQuery)
+	}
+
+	req := bleve.NewSearchRequest(searchQuery)
+	if limit > 0 {
+		req.Size = limit
+	}
+	req.Fields = []string{"namespace"}
+	req.Highlight = bleve.NewHighlight()
+	req.Highlight.AddField("body")
+
+	res, err := s.index.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("search: query %q: %w", q, err)
+	}
+
+	results := make([]Result, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		namespace, _ := hit.Fields["namespace"].(string)
+		results = append(results, Result{
+			Title:     hit.ID,
+			Namespace: namespace,
+			Snippet:   firstFragment(hit.Fragments["body"]),
+			Score:     hit.Score,
+		})
+	}
+	return results, nil
+}
+
+func firstFragment(fragments []string) string {
+	if len(fragments) == 0 {
+		return ""
+	}
+	return fragments[0]
+}
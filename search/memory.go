@@ -0,0 +1,116 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemorySearcher is a Searcher backed by a plain map and substring
+// matching - no real inverted index, just enough to exercise the Searcher
+// contract in tests without pulling in bleve.
+type MemorySearcher struct {
+	mu   sync.RWMutex
+	docs map[string]memoryDoc
+}
+
+type memoryDoc struct {
+	namespace string
+	body      string
+}
+
+// NewMemorySearcher returns an empty MemorySearcher.
+func NewMemorySearcher() *MemorySearcher {
+	return &MemorySearcher{docs: make(map[string]memoryDoc)}
+}
+
+// Index implements Searcher.
+func (s *MemorySearcher) Index(title, namespace, body string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.docs[title] = memoryDoc{namespace: namespace, body: body}
+	return nil
+}
+
+// Delete implements Searcher.
+func (s *MemorySearcher) Delete(title string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.docs, title)
+	return nil
+}
+
+// Search implements Searcher. It matches titles and bodies
+// case-insensitively as a plain substring, scoring a title match above a
+// body-only match.
+func (s *MemorySearcher) Search(query, namespace string, limit int) ([]Result, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	needle := strings.ToLower(strings.TrimSpace(query))
+	if needle == "" {
+		return nil, nil
+	}
+
+	var results []Result
+	for title, doc := range s.docs {
+		if namespace != "" && doc.namespace != namespace {
+			continue
+		}
+		lowerBody := strings.ToLower(doc.body)
+		titleHit := strings.Contains(strings.ToLower(title), needle)
+		bodyIdx := strings.Index(lowerBody, needle)
+		if !titleHit && bodyIdx < 0 {
+			continue
+		}
+
+		score := 0.5
+		if titleHit {
+			score = 1.0
+		}
+		results = append(results, Result{
+			Title:     title,
+			Namespace: doc.namespace,
+			Snippet:   snippet(doc.body, bodyIdx, len(needle)),
+			Score:     score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// snippet returns up to ~60 characters of context around the match at idx
+// (or the start of body if there was no body match), with the matched
+// span wrapped in <mark> for highlighting.
+func snippet(body string, idx, matchLen int) string {
+	if idx < 0 {
+		if len(body) > 60 {
+			return body[:60] + "..."
+		}
+		return body
+	}
+
+	start := idx - 30
+	if start < 0 {
+		start = 0
+	}
+	end := idx + matchLen + 30
+	if end > len(body) {
+		end = len(body)
+	}
+
+	prefix := ""
+	if start > 0 {
+		prefix = "..."
+	}
+	suffix := ""
+	if end < len(body) {
+		suffix = "..."
+	}
+
+	return prefix + body[start:idx] + "<mark>" + body[idx:idx+matchLen] + "</mark>" + body[idx+matchLen:end] + suffix
+}
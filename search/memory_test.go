@@ -0,0 +1,61 @@
+package search
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemorySearcher(t *testing.T) {
+	s := NewMemorySearcher()
+
+	if err := s.Index("Golang", "Main", "Go is a statically typed, compiled language."); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := s.Index("Python", "Main", "Python is a dynamically typed language."); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+	if err := s.Index("Golang Tutorial", "Help", "An introduction to Go."); err != nil {
+		t.Fatalf("Index: %v", err)
+	}
+
+	results, err := s.Search("golang", "", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search(golang) = %+v, want both titles containing \"golang\"", results)
+	}
+
+	results, err = s.Search("language", "", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Search(language) returned %d results, want 2", len(results))
+	}
+	if !strings.Contains(results[0].Snippet, "<mark>language</mark>") {
+		t.Errorf("Snippet = %q, want it to wrap the match in <mark>", results[0].Snippet)
+	}
+
+	results, err = s.Search("language", "Help", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Search(language, namespace=Help) = %+v, want no matches", results)
+	}
+
+	if err := s.Delete("Golang"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete("Golang Tutorial"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	results, err = s.Search("golang", "", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("Search(golang) after Delete = %+v, want no matches", results)
+	}
+}
@@ -0,0 +1,30 @@
+// Package search defines the Searcher interface for querying a wiki's
+// pages by text, plus an in-memory implementation for tests and a
+// bleve-backed one for production.
+package search
+
+// Result is one match returned from a Search call.
+type Result struct {
+	Title     string
+	Namespace string
+	Snippet   string
+	Score     float64
+}
+
+// Searcher maintains a searchable index over wiki pages and answers text
+// queries against it. Implementations must make Index/Delete safe to call
+// concurrently with Search.
+type Searcher interface {
+	// Index adds or replaces the indexed content for title. Called from
+	// SavePage after every save, so it should be cheap enough to run
+	// inline on the request path.
+	Index(title, namespace, body string) error
+
+	// Delete removes title from the index.
+	Delete(title string) error
+
+	// Search returns matches for query, best first, capped at limit. If
+	// namespace is non-empty, only pages indexed under that namespace are
+	// considered.
+	Search(query, namespace string, limit int) ([]Result, error)
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore is a PageStore backed by plain files on disk, in the spirit of
+// the Go wiki tutorial's `.txt` pages: each revision's body is a `.mw` file,
+// and a `.meta.json` sidecar per title records the revision list (author,
+// timestamp, comment). Construct one with NewFileStore.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+type fileMeta struct {
+	NextID    int                `json:"nextId"`
+	Revisions []fileRevisionMeta `json:"revisions"`
+}
+
+type fileRevisionMeta struct {
+	ID        int       `json:"id"`
+	Author    string    `json:"author"`
+	Comment   string    `json:"comment"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// NewFileStore returns a FileStore rooted at dir, creating dir if it
+// doesn't already exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create store dir: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) metaPath(title string) string {
+	return filepath.Join(s.dir, title+".meta.json")
+}
+
+func (s *FileStore) bodyPath(title string, id int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.%d.mw", title, id))
+}
+
+func (s *FileStore) readMeta(title string) (*fileMeta, error) {
+	data, err := os.ReadFile(s.metaPath(title))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var meta fileMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func (s *FileStore) writeMeta(title string, meta *fileMeta) error {
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.metaPath(title), data, 0o644)
+}
+
+// Get implements PageStore.
+func (s *FileStore) Get(title string) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, err := s.readMeta(title)
+	if err != nil {
+		return nil, err
+	}
+	latest := meta.Revisions[len(meta.Revisions)-1]
+	return s.loadRevision(title, latest)
+}
+
+// Save implements PageStore.
+func (s *FileStore) Save(title, body, author, comment string) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, err := s.readMeta(title)
+	if err != nil {
+		if err != ErrNotFound {
+			return nil, err
+		}
+		meta = &fileMeta{NextID: 1}
+	}
+
+	id := meta.NextID
+	rev := &Revision{ID: id, Title: title, Body: body, Author: author, Comment: comment, Timestamp: time.Now()}
+
+	if err := os.WriteFile(s.bodyPath(title, id), []byte(body), 0o644); err != nil {
+		return nil, err
+	}
+	meta.NextID = id + 1
+	meta.Revisions = append(meta.Revisions, fileRevisionMeta{ID: id, Author: author, Comment: comment, Timestamp: rev.Timestamp})
+	if err := s.writeMeta(title, meta); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// History implements PageStore.
+func (s *FileStore) History(title string) ([]*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, err := s.readMeta(title)
+	if err != nil {
+		return nil, err
+	}
+	revs := make([]*Revision, 0, len(meta.Revisions))
+	for _, rm := range meta.Revisions {
+		rev, err := s.loadRevision(title, rm)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+	return revs, nil
+}
+
+// Revision implements PageStore.
+func (s *FileStore) Revision(title string, id int) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meta, err := s.readMeta(title)
+	if err != nil {
+		return nil, err
+	}
+	for _, rm := range meta.Revisions {
+		if rm.ID == id {
+			return s.loadRevision(title, rm)
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Exists implements PageStore.
+func (s *FileStore) Exists(title string) bool {
+	if ValidateTitle(title) != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.readMeta(title)
+	return err == nil
+}
+
+// Titles implements PageStore.
+func (s *FileStore) Titles() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var titles []string
+	for _, entry := range entries {
+		if title, ok := strings.CutSuffix(entry.Name(), ".meta.json"); ok {
+			titles = append(titles, title)
+		}
+	}
+	return titles, nil
+}
+
+// RecentChanges implements PageStore.
+func (s *FileStore) RecentChanges(limit int) ([]*Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var revs []*Revision
+	for _, entry := range entries {
+		title, ok := strings.CutSuffix(entry.Name(), ".meta.json")
+		if !ok {
+			continue
+		}
+		meta, err := s.readMeta(title)
+		if err != nil {
+			return nil, err
+		}
+		for _, rm := range meta.Revisions {
+			rev, err := s.loadRevision(title, rm)
+			if err != nil {
+				return nil, err
+			}
+			revs = append(revs, rev)
+		}
+	}
+
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Timestamp.After(revs[j].Timestamp) })
+	if limit > 0 && len(revs) > limit {
+		revs = revs[:limit]
+	}
+	return revs, nil
+}
+
+func (s *FileStore) loadRevision(title string, rm fileRevisionMeta) (*Revision, error) {
+	body, err := os.ReadFile(s.bodyPath(title, rm.ID))
+	if err != nil {
+		return nil, err
+	}
+	return &Revision{
+		ID:        rm.ID,
+		Title:     title,
+		Body:      string(body),
+		Author:    rm.Author,
+		Comment:   rm.Comment,
+		Timestamp: rm.Timestamp,
+	}, nil
+}
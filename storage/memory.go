@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory PageStore, primarily useful for tests. The
+// zero value is not ready to use; construct one with NewMemoryStore.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	history map[string][]*Revision
+	nowFunc func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		history: make(map[string][]*Revision),
+		nowFunc: time.Now,
+	}
+}
+
+// Get implements PageStore.
+func (s *MemoryStore) Get(title string) (*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revs := s.history[title]
+	if len(revs) == 0 {
+		return nil, ErrNotFound
+	}
+	return revs[len(revs)-1], nil
+}
+
+// Save implements PageStore.
+func (s *MemoryStore) Save(title, body, author, comment string) (*Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rev := &Revision{
+		ID:        len(s.history[title]) + 1,
+		Title:     title,
+		Body:      body,
+		Author:    author,
+		Comment:   comment,
+		Timestamp: s.nowFunc(),
+	}
+	s.history[title] = append(s.history[title], rev)
+	return rev, nil
+}
+
+// History implements PageStore.
+func (s *MemoryStore) History(title string) ([]*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	revs := s.history[title]
+	if len(revs) == 0 {
+		return nil, ErrNotFound
+	}
+	out := make([]*Revision, len(revs))
+	copy(out, revs)
+	return out, nil
+}
+
+// Revision implements PageStore.
+func (s *MemoryStore) Revision(title string, id int) (*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rev := range s.history[title] {
+		if rev.ID == id {
+			return rev, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// Exists implements PageStore.
+func (s *MemoryStore) Exists(title string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.history[title]) > 0
+}
+
+// Titles implements PageStore.
+func (s *MemoryStore) Titles() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	titles := make([]string, 0, len(s.history))
+	for title, revs := range s.history {
+		if len(revs) > 0 {
+			titles = append(titles, title)
+		}
+	}
+	return titles, nil
+}
+
+// RecentChanges implements PageStore.
+func (s *MemoryStore) RecentChanges(limit int) ([]*Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var revs []*Revision
+	for _, titleRevs := range s.history {
+		revs = append(revs, titleRevs...)
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].Timestamp.After(revs[j].Timestamp) })
+	if limit > 0 && len(revs) > limit {
+		revs = revs[:limit]
+	}
+	return revs, nil
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore is a PageStore backed by a SQLite database, for deployments
+// that want revision history queryable with SQL without running a separate
+// database server. Construct one with NewSQLiteStore.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database at path
+// and ensures its schema exists.
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("storage: open sqlite: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS revisions (
+			id        INTEGER NOT NULL,
+			title     TEXT    NOT NULL,
+			body      TEXT    NOT NULL,
+			author    TEXT    NOT NULL,
+			comment   TEXT    NOT NULL,
+			timestamp DATETIME NOT NULL,
+			PRIMARY KEY (title, id)
+		)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("storage: create schema: %w", err)
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close releases the underlying database connection.
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Get implements PageStore.
+func (s *SQLiteStore) Get(title string) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRow(`SELECT id, body, author, comment, timestamp FROM revisions
+		WHERE title = ? ORDER BY id DESC LIMIT 1`, title)
+	return scanRevision(row, title)
+}
+
+// Save implements PageStore.
+func (s *SQLiteStore) Save(title, body, author, comment string) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var nextID int
+	err = tx.QueryRow(`SELECT COALESCE(MAX(id), 0) + 1 FROM revisions WHERE title = ?`, title).Scan(&nextID)
+	if err != nil {
+		return nil, err
+	}
+
+	rev := &Revision{ID: nextID, Title: title, Body: body, Author: author, Comment: comment, Timestamp: time.Now()}
+	_, err = tx.Exec(`INSERT INTO revisions (id, title, body, author, comment, timestamp) VALUES (?, ?, ?, ?, ?, ?)`,
+		rev.ID, rev.Title, rev.Body, rev.Author, rev.Comment, rev.Timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+// History implements PageStore.
+func (s *SQLiteStore) History(title string) ([]*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(`SELECT id, body, author, comment, timestamp FROM revisions
+		WHERE title = ? ORDER BY id ASC`, title)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revs []*Revision
+	for rows.Next() {
+		rev := &Revision{Title: title}
+		if err := rows.Scan(&rev.ID, &rev.Body, &rev.Author, &rev.Comment, &rev.Timestamp); err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(revs) == 0 {
+		return nil, ErrNotFound
+	}
+	return revs, nil
+}
+
+// Revision implements PageStore.
+func (s *SQLiteStore) Revision(title string, id int) (*Revision, error) {
+	if err := ValidateTitle(title); err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRow(`SELECT id, body, author, comment, timestamp FROM revisions
+		WHERE title = ? AND id = ?`, title, id)
+	return scanRevision(row, title)
+}
+
+// Exists implements PageStore.
+func (s *SQLiteStore) Exists(title string) bool {
+	if ValidateTitle(title) != nil {
+		return false
+	}
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM revisions WHERE title = ?`, title).Scan(&count)
+	return err == nil && count > 0
+}
+
+// Titles implements PageStore.
+func (s *SQLiteStore) Titles() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT title FROM revisions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var titles []string
+	for rows.Next() {
+		var title string
+		if err := rows.Scan(&title); err != nil {
+			return nil, err
+		}
+		titles = append(titles, title)
+	}
+	return titles, rows.Err()
+}
+
+// RecentChanges implements PageStore.
+func (s *SQLiteStore) RecentChanges(limit int) ([]*Revision, error) {
+	query := `SELECT id, title, body, author, comment, timestamp FROM revisions ORDER BY timestamp DESC`
+	args := []any{}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revs []*Revision
+	for rows.Next() {
+		rev := &Revision{}
+		if err := rows.Scan(&rev.ID, &rev.Title, &rev.Body, &rev.Author, &rev.Comment, &rev.Timestamp); err != nil {
+			return nil, err
+		}
+		revs = append(revs, rev)
+	}
+	return revs, rows.Err()
+}
+
+func scanRevision(row *sql.Row, title string) (*Revision, error) {
+	rev := &Revision{Title: title}
+	if err := row.Scan(&rev.ID, &rev.Body, &rev.Author, &rev.Comment, &rev.Timestamp); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rev, nil
+}
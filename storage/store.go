@@ -0,0 +1,55 @@
+// Package storage defines the PageStore interface used to persist wiki
+// pages and their revision history, plus filesystem, SQLite, and in-memory
+// implementations of it.
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by PageStore methods when the requested title or
+// revision does not exist.
+var ErrNotFound = errors.New("storage: page not found")
+
+// Revision is one immutable save of a page.
+type Revision struct {
+	ID        int
+	Title     string
+	Body      string
+	Author    string
+	Comment   string
+	Timestamp time.Time
+}
+
+// PageStore persists wiki pages as an append-only sequence of revisions per
+// title. Implementations must make Save atomic with respect to concurrent
+// callers: two Saves for the same title must not silently clobber one
+// another's revision IDs.
+type PageStore interface {
+	// Get returns the latest revision of title, or ErrNotFound if it has
+	// never been saved.
+	Get(title string) (*Revision, error)
+
+	// Save appends a new revision for title and returns it.
+	Save(title, body, author, comment string) (*Revision, error)
+
+	// History returns every revision of title, oldest first, or
+	// ErrNotFound if it has never been saved.
+	History(title string) ([]*Revision, error)
+
+	// Revision returns a specific past revision of title by ID, or
+	// ErrNotFound if title or that revision doesn't exist.
+	Revision(title string, id int) (*Revision, error)
+
+	// Exists reports whether title has ever been saved.
+	Exists(title string) bool
+
+	// Titles returns every page title that has ever been saved, in no
+	// particular order. Used to (re)build a search index from scratch.
+	Titles() ([]string, error)
+
+	// RecentChanges returns the most recent revisions across every page,
+	// newest first, capped at limit. Used to power the recent-changes feed.
+	RecentChanges(limit int) ([]*Revision, error)
+}
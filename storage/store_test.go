@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testPageStoreContract(t, NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	s, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+	testPageStoreContract(t, s)
+}
+
+// testPageStoreContract exercises the save/get/history/revision/recent-changes
+// contract every PageStore implementation must satisfy identically.
+func testPageStoreContract(t *testing.T, s PageStore) {
+	t.Helper()
+
+	if s.Exists("Golang") {
+		t.Fatal("Exists returned true for a page never saved")
+	}
+	if _, err := s.Get("Golang"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get on unsaved page = %v, want ErrNotFound", err)
+	}
+
+	rev1, err := s.Save("Golang", "first body", "alice", "create")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if rev1.ID != 1 {
+		t.Fatalf("first revision ID = %d, want 1", rev1.ID)
+	}
+
+	rev2, err := s.Save("Golang", "second body", "bob", "edit")
+	if err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if rev2.ID != 2 {
+		t.Fatalf("second revision ID = %d, want 2", rev2.ID)
+	}
+
+	if !s.Exists("Golang") {
+		t.Fatal("Exists returned false for a saved page")
+	}
+
+	got, err := s.Get("Golang")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Body != "second body" {
+		t.Fatalf("Get returned body %q, want latest revision", got.Body)
+	}
+
+	history, err := s.History("Golang")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 2 || history[0].ID != 1 || history[1].ID != 2 {
+		t.Fatalf("History = %+v, want [rev1, rev2] oldest first", history)
+	}
+
+	got, err = s.Revision("Golang", 1)
+	if err != nil {
+		t.Fatalf("Revision: %v", err)
+	}
+	if got.Body != "first body" {
+		t.Fatalf("Revision(1) body = %q, want %q", got.Body, "first body")
+	}
+
+	if _, err := s.Revision("Golang", 99); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Revision on unknown ID = %v, want ErrNotFound", err)
+	}
+
+	titles, err := s.Titles()
+	if err != nil {
+		t.Fatalf("Titles: %v", err)
+	}
+	if len(titles) != 1 || titles[0] != "Golang" {
+		t.Fatalf("Titles = %v, want [Golang]", titles)
+	}
+
+	recent, err := s.RecentChanges(1)
+	if err != nil {
+		t.Fatalf("RecentChanges: %v", err)
+	}
+	if len(recent) != 1 || recent[0].ID != 2 {
+		t.Fatalf("RecentChanges(1) = %+v, want most recent revision first", recent)
+	}
+}
+
+func TestValidateTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		valid bool
+	}{
+		{"Golang", true},
+		{"Go_Programming:Intro", true},
+		{"Page Title", true},
+		{"", false},
+		{" LeadingSpace", false},
+		{"../etc/passwd", false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateTitle(tt.title)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateTitle(%q) = %v, want nil", tt.title, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidTitle) {
+			t.Errorf("ValidateTitle(%q) = %v, want ErrInvalidTitle", tt.title, err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrInvalidTitle is returned by ValidateTitle for a title that could escape
+// the store (path traversal, absolute paths) or that simply isn't a
+// reasonable page name.
+var ErrInvalidTitle = errors.New("storage: invalid page title")
+
+// validTitleRegex is deliberately narrow, mirroring the Go wiki codewalk's
+// fix for validPath: it allows letters, digits, spaces, and a conservative
+// set of punctuation used in real MediaWiki titles (namespace ":", "-",
+// "_", "."), and nothing that could be interpreted as a path segment like
+// ".." or a leading "/".
+var validTitleRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._:-]*$`)
+
+// ValidateTitle reports whether title is safe to use as a PageStore key and,
+// for filesystem-backed stores, as part of a file path. FileStore and
+// SQLiteStore call it in every method that takes a title (MemoryStore, which
+// builds no paths, does not), and every HTTP handler that takes a title from
+// a request must call it before touching storage.
+func ValidateTitle(title string) error {
+	if !validTitleRegex.MatchString(title) {
+		return ErrInvalidTitle
+	}
+	return nil
+}